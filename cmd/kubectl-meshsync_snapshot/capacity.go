@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/capacity"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+func runCapacity(args []string) int {
+	fs := newSubcommandFlagSet("capacity", "<snapshot> [--format table|json] [-n namespace]")
+	format := fs.String("format", capacity.FormatTable, "Output format: table or json")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only account namespaces and workloads in this namespace (Node totals stay cluster-wide)")
+	fs.StringVar(&options.Namespace, "n", "", "Only account namespaces and workloads in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	report := capacity.Build(snap, capacity.Options{Namespace: options.Namespace})
+	if err := report.Write(os.Stdout, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing capacity report: %v\n", err)
+		return 1
+	}
+	return 0
+}
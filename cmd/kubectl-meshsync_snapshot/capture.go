@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/crds"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/meshsync"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/metrics"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/nats"
+	natsd "github.com/nats-io/nats-server/v2/server"
+)
+
+const captureNATSURL = "nats://localhost:4222"
+
+// captureSession is the temporary infrastructure a capture needs: a local
+// NATS server, the MeshSync CRDs and instance, and the MeshSync process.
+type captureSession struct {
+	options     *models.Options
+	natsServer  *natsd.Server
+	crdManager  *crds.Manager
+	meshSyncCmd *exec.Cmd
+	closeOnce   sync.Once
+}
+
+// startCaptureSession brings everything up. beforeMeshSync, if set, runs
+// once NATS is ready and before MeshSync starts publishing. On failure
+// whatever was already started is torn down again, so nothing is left in
+// the cluster.
+func startCaptureSession(options *models.Options, beforeMeshSync func() error) (*captureSession, error) {
+	meshsyncPath, err := findMeshSyncBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find MeshSync binary: %w", err)
+	}
+
+	s := &captureSession{
+		options:    options,
+		crdManager: crds.NewManager("pkg/crds/meshery-crds.yaml", options),
+	}
+	// In a pod, the "nats" host alias comes from the pod spec instead.
+	if !s.crdManager.InCluster() {
+		setupHostsEntry()
+	}
+
+	var wg sync.WaitGroup
+	var natsErr, crdErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.natsServer, natsErr = nats.StartServer(options)
+	}()
+	go func() {
+		defer wg.Done()
+		crdErr = s.crdManager.Apply()
+	}()
+	wg.Wait()
+	metrics.SetNATSServer(s.natsServer)
+
+	if natsErr != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to start NATS server: %w", natsErr)
+	}
+	if crdErr != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to apply CRDs: %w", crdErr)
+	}
+
+	if beforeMeshSync != nil {
+		if err := beforeMeshSync(); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	s.meshSyncCmd, err = meshsync.Run("nats:4222", meshsyncPath, options)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to start MeshSync: %w", err)
+	}
+	return s, nil
+}
+
+// Alive reports whether the MeshSync process is still running.
+func (s *captureSession) Alive() bool {
+	return s.meshSyncCmd != nil && s.meshSyncCmd.Process != nil &&
+		s.meshSyncCmd.Process.Signal(syscall.Signal(0)) == nil
+}
+
+// Close stops MeshSync, removes the CRDs and the meshery namespace, and
+// shuts down NATS. It is safe to call more than once.
+func (s *captureSession) Close() {
+	s.closeOnce.Do(func() {
+		if s.meshSyncCmd != nil && s.meshSyncCmd.Process != nil {
+			slog.Debug("Terminating MeshSync process")
+			meshsync.KillProcessGroup(s.meshSyncCmd)
+		}
+
+		if s.crdManager != nil {
+			s.crdManager.Remove()
+		}
+		if s.meshSyncCmd != nil {
+			if err := deleteNamespace("meshery"); err != nil {
+				slog.Warn("Failed to delete namespace", "namespace", "meshery", "error", err)
+			} else {
+				slog.Info("Namespace deleted", "namespace", "meshery")
+			}
+		}
+
+		if s.natsServer != nil {
+			slog.Debug("Shutting down NATS server")
+			metrics.SetNATSServer(nil)
+			s.natsServer.Shutdown()
+		}
+	})
+}
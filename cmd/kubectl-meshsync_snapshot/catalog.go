@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/catalog"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/crds"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func addCatalogFlag(fs *flag.FlagSet) *string {
+	return fs.String("catalog", "", "Snapshot catalog database (default ~/.kube/meshsync-snapshots/catalog.db)")
+}
+
+func openCatalog(path string) (*catalog.Catalog, error) {
+	if path == "" {
+		defaultPath, err := catalog.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return catalog.Open(path)
+}
+
+// recordSnapshot indexes a freshly saved snapshot in the catalog.
+func recordSnapshot(catalogPath string, normalize bool, absOutputPath string, resources []*models.KubernetesResource, options *models.Options) (int64, error) {
+	c, err := openCatalog(catalogPath)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	entry := &catalog.Entry{
+		ClusterID: snapshot.ClusterID(resources),
+		Context:   currentKubeContext(),
+		Timestamp: time.Now(),
+		Path:      absOutputPath,
+		Filters:   snapshot.FilterOptions(options),
+		Resources: len(resources),
+	}
+	if info, err := os.Stat(absOutputPath); err == nil {
+		entry.Size = info.Size()
+	}
+	return c.Record(entry, resources, normalize)
+}
+
+func currentKubeContext() string {
+	if crds.InCluster() {
+		return "in-cluster"
+	}
+	out, err := exec.Command("kubectl", "config", "current-context").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func runHistory(args []string) int {
+	fs := newSubcommandFlagSet("history", "[--cluster id] [--limit n] [--format table|json]")
+	catalogPath := addCatalogFlag(fs)
+	cluster := fs.String("cluster", "", "Only show snapshots of this cluster ID")
+	limit := fs.Int("limit", 20, "Show at most this many snapshots (0 for all)")
+	format := fs.String("format", "table", "Output format: table or json")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 0 || (*format != "table" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	c, err := openCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+	entries, err := c.List(*cluster, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		if entries == nil {
+			entries = []*catalog.Entry{}
+		}
+		return printJSON(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No snapshots recorded in %s\n", c.Path())
+		return 0
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTAKEN\tCLUSTER\tCONTEXT\tRESOURCES\tSIZE\tPATH")
+	for _, entry := range entries {
+		path := entry.Path
+		if _, err := os.Stat(path); err != nil {
+			path += " (missing)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", entry.ID, entry.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			entry.ClusterID, valueOr(entry.Context, "-"), entry.Resources, utils.FormatSize(entry.Size), path)
+	}
+	tw.Flush()
+	return 0
+}
+
+func runShow(args []string) int {
+	fs := newSubcommandFlagSet("show", "<id> [--format text|json]")
+	catalogPath := addCatalogFlag(fs)
+	format := fs.String("format", "text", "Output format: text or json")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+	id, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid snapshot ID %q\n", positional[0])
+		return 2
+	}
+
+	c, err := openCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+	entry, err := c.Get(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		return printJSON(entry)
+	}
+
+	fmt.Printf("ID:         %d\n", entry.ID)
+	fmt.Printf("Taken:      %s\n", entry.Timestamp.Local().Format(time.RFC3339))
+	fmt.Printf("Cluster:    %s\n", entry.ClusterID)
+	fmt.Printf("Context:    %s\n", valueOr(entry.Context, "-"))
+	fmt.Printf("Path:       %s\n", entry.Path)
+	fmt.Printf("Size:       %s\n", utils.FormatSize(entry.Size))
+	fmt.Printf("Resources:  %d\n", entry.Resources)
+
+	var filterKeys []string
+	for key, value := range entry.Filters {
+		if value != nil && value != "" && value != false {
+			filterKeys = append(filterKeys, key)
+		}
+	}
+	sort.Strings(filterKeys)
+	if len(filterKeys) > 0 {
+		fmt.Println("Filters:")
+		for _, key := range filterKeys {
+			fmt.Printf("  %s: %v\n", key, entry.Filters[key])
+		}
+	}
+
+	kinds := make([]string, 0, len(entry.Kinds))
+	for kind := range entry.Kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Println("Kinds:")
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, kind := range kinds {
+		fmt.Fprintf(tw, "  %s\t%d\n", kind, entry.Kinds[kind])
+	}
+	tw.Flush()
+	return 0
+}
+
+func runPrune(args []string) int {
+	fs := newSubcommandFlagSet("prune", "--keep n [--cluster id] [--keep-files] [--dry-run]")
+	catalogPath := addCatalogFlag(fs)
+	keep := fs.Int("keep", 0, "Number of most recent snapshots to keep per cluster")
+	cluster := fs.String("cluster", "", "Only prune snapshots of this cluster ID")
+	keepFiles := fs.Bool("keep-files", false, "Remove catalog entries but leave the snapshot files on disk")
+	dryRun := fs.Bool("dry-run", false, "Show what would be pruned without removing anything")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	keepSet := false
+	fs.Visit(func(f *flag.Flag) {
+		keepSet = keepSet || f.Name == "keep"
+	})
+	if len(positional) != 0 || !keepSet || *keep < 0 {
+		fs.Usage()
+		return 2
+	}
+
+	c, err := openCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+	expired, err := c.Expired(*keep, *cluster)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(expired) == 0 {
+		fmt.Println("Nothing to prune")
+		return 0
+	}
+
+	verb := "Pruned"
+	if *dryRun {
+		verb = "Would prune"
+	}
+	expiredIDs := make([]int64, 0, len(expired))
+	for _, entry := range expired {
+		expiredIDs = append(expiredIDs, entry.ID)
+	}
+	ids := make([]int64, 0, len(expired))
+	status := 0
+	for _, entry := range expired {
+		note := ""
+		// A kept entry may record the same file, as every capture without
+		// --auto-name writes meshsync-snapshot.json.
+		inUse, err := c.PathInUse(entry.Path, expiredIDs...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if inUse {
+			note = " (file kept: another entry records it)"
+		} else if !*keepFiles && !*dryRun {
+			if err := removeSnapshotFiles(entry.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error removing %s: %v\n", entry.Path, err)
+				status = 1
+				continue
+			}
+		}
+		ids = append(ids, entry.ID)
+		fmt.Printf("%s %d (%s, %s) %s%s\n", verb, entry.ID, entry.ClusterID, entry.Timestamp.Local().Format("2006-01-02 15:04:05"), entry.Path, note)
+	}
+	if *dryRun {
+		return status
+	}
+	if err := c.Delete(ids...); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return status
+}
+
+// removeSnapshotFiles deletes a snapshot and its detached signature. Files
+// that are already gone are not an error.
+func removeSnapshotFiles(path string) error {
+	for _, file := range []string{path, path + snapshot.SignatureExtension} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func printJSON(value interface{}) int {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(append(data, '\n'))
+	return 0
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
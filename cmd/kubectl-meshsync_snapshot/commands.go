@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/logging"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/selectors"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/storage"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+var subcommands = map[string]func(args []string) int{
+	"capacity":   runCapacity,
+	"daemon":     runDaemon,
+	"deploy-job": runDeployJob,
+	"export":     runExport,
+	"fetch":      runFetch,
+	"graph":      runGraph,
+	"history":    runHistory,
+	"images":     runImages,
+	"lint":       runLint,
+	"list":       runList,
+	"orphans":    runOrphans,
+	"prune":      runPrune,
+	"publish":    runPublish,
+	"push":       runPush,
+	"query":      runQuery,
+	"report":     runReport,
+	"serve":      runServe,
+	"show":       runShow,
+	"tree":       runTree,
+	"verify":     runVerify,
+}
+
+func newSubcommandFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: kubectl meshsync-snapshot %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func addDecryptionFlags(fs *flag.FlagSet, options *models.Options) {
+	fs.StringVar(&options.IdentityFile, "identity", options.IdentityFile, "age identity file used to decrypt encrypted snapshots")
+	fs.StringVar(&options.PassphraseFile, "passphrase-file", options.PassphraseFile, "File containing the passphrase for passphrase-encrypted snapshots")
+}
+
+// addLogFlags registers --log-level and --log-format. Logs always go to
+// stderr so that stdout only carries results.
+func addLogFlags(fs *flag.FlagSet) (level, format *string) {
+	level = fs.String("log-level", "", "Log level: debug, info, warn or error (default: info, debug with -v, warn with -q)")
+	format = fs.String("log-format", logging.FormatText, "Log format: text or json")
+	return level, format
+}
+
+// parseSubcommand adds the log flags to fs, parses args with flags and
+// positionals interspersed, checks the label selector and sets up logging.
+// options may be nil for subcommands without -v, -q or -l.
+func parseSubcommand(fs *flag.FlagSet, args []string, options *models.Options) ([]string, error) {
+	level, format := addLogFlags(fs)
+	positional, err := utils.ParseInterspersed(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = models.NewDefaultOptions()
+	}
+	if _, err := selectors.ParseLabelSelector(options.LabelSelector); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, err
+	}
+	if err := setupLogging(*level, *format, options); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, err
+	}
+	return positional, nil
+}
+
+// setupLogging configures the default logger. Without an explicit level,
+// -v and -q pick debug and warn.
+func setupLogging(level, format string, options *models.Options) error {
+	if level == "" {
+		switch {
+		case options.VerboseMode:
+			level = "debug"
+		case options.QuietMode:
+			level = "warn"
+		default:
+			level = "info"
+		}
+	}
+	parsed, err := logging.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	return logging.Setup(os.Stderr, parsed, format)
+}
+
+// addS3Flags registers S3 connection flags. Anything left unset falls back
+// to AWS_ENDPOINT_URL, AWS_REGION, AWS_ACCESS_KEY_ID and friends.
+func addS3Flags(fs *flag.FlagSet, cfg *storage.S3Config) {
+	fs.StringVar(&cfg.Endpoint, "s3-endpoint", cfg.Endpoint, "S3 endpoint, e.g. http://localhost:9000 for MinIO (default: AWS)")
+	fs.StringVar(&cfg.Region, "s3-region", cfg.Region, "S3 region")
+	fs.StringVar(&cfg.AccessKey, "s3-access-key", cfg.AccessKey, "S3 access key ID")
+	fs.StringVar(&cfg.SecretKey, "s3-secret-key", cfg.SecretKey, "S3 secret access key")
+	fs.BoolVar(&cfg.PathStyle, "s3-path-style", cfg.PathStyle, "Use path-style bucket addressing (required by MinIO and most S3-compatible stores)")
+	fs.BoolVar(&cfg.Insecure, "s3-insecure", cfg.Insecure, "Use plain HTTP when the endpoint has no scheme")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/catalog"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/meshsync"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/metrics"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/retention"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+type daemon struct {
+	options          *models.Options
+	dir              string
+	base             string
+	keepWarm         bool
+	policy           retention.Policy
+	webhook          string
+	webhookOnSuccess bool
+	catalogPath      string
+	noCatalog        bool
+	catalogResources bool
+
+	session *captureSession
+	store   *meshsync.Store
+}
+
+// webhookEvent is the JSON body POSTed to --webhook.
+type webhookEvent struct {
+	Event               string    `json:"event"`
+	Time                time.Time `json:"time"`
+	ClusterID           string    `json:"cluster_id,omitempty"`
+	Path                string    `json:"path,omitempty"`
+	Resources           int       `json:"resources,omitempty"`
+	Error               string    `json:"error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures,omitempty"`
+}
+
+func runDaemon(args []string) int {
+	fs := newSubcommandFlagSet("daemon", "--interval d | --schedule cron [--dir dir] [--retention hourly=24,daily=30] [--keep-warm] [--webhook url]")
+	options := models.NewDefaultOptions()
+	d := &daemon{options: options}
+	interval := fs.Duration("interval", 0, "Take a snapshot at this interval, starting immediately (e.g. 15m, 1h)")
+	schedule := fs.String("schedule", "", "Take snapshots on this cron schedule (5-field cron or @hourly, @daily, ...)")
+	fs.StringVar(&d.dir, "dir", ".", "Directory snapshots are written to")
+	fs.StringVar(&options.OutputFile, "output", options.OutputFile, "Base file name; each snapshot gets a timestamp suffix")
+	fs.StringVar(&options.OutputFile, "o", options.OutputFile, "Base file name (shorthand)")
+	retentionPolicy := fs.String("retention", "", "Snapshots to keep, e.g. hourly=24,daily=30 (periods: last, hourly, daily, weekly, monthly)")
+	fs.BoolVar(&d.keepWarm, "keep-warm", false, "Keep NATS and MeshSync running between snapshots instead of restarting them per run")
+	fs.StringVar(&d.webhook, "webhook", "", "POST a JSON event to this URL when a snapshot fails")
+	fs.BoolVar(&d.webhookOnSuccess, "webhook-on-success", false, "Also POST to --webhook after every successful snapshot")
+	metricsAddr := fs.String("metrics-addr", "", "Serve Prometheus metrics on this address at /metrics (e.g. :9090)")
+	maxFailures := fs.Int("max-failures", 3, "Exit with status 1 after this many consecutive failures (0 to never give up)")
+	catalogPath := addCatalogFlag(fs)
+	fs.BoolVar(&d.noCatalog, "no-catalog", false, "Do not record snapshots in the catalog")
+	fs.BoolVar(&d.catalogResources, "catalog-resources", false, "Also store every resource in the catalog")
+	fs.StringVar(&options.Namespace, "namespace", "", "Filter resources by namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Filter resources by namespace (shorthand)")
+	fs.StringVar(&options.ResourceType, "type", "", "Filter resources by type")
+	fs.StringVar(&options.ResourceType, "t", "", "Filter resources by type (shorthand)")
+	fs.StringVar(&options.LabelSelector, "selector", "", "Filter resources by label selector")
+	fs.StringVar(&options.LabelSelector, "l", "", "Filter resources by label selector (shorthand)")
+	excludeStr := fs.String("exclude", "", "Comma-separated list of resource types to exclude")
+	fs.BoolVar(&options.FastMode, "fast", false, "Capture only essential resources")
+	waitTime := fs.Int("time", int(options.CollectionTime.Seconds()), "Collection time in seconds")
+	fs.BoolVar(&options.VerboseMode, "verbose", false, "Detailed output")
+	fs.BoolVar(&options.VerboseMode, "v", false, "Detailed output (shorthand)")
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 0 || (*interval == 0) == (*schedule == "") || *interval < 0 {
+		fs.Usage()
+		return 2
+	}
+	d.catalogPath = *catalogPath
+
+	var next func(time.Time) time.Time
+	if *interval > 0 {
+		next = func(t time.Time) time.Time { return t.Add(*interval) }
+	} else {
+		cronSchedule, err := cron.ParseStandard(*schedule)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --schedule %q: %v\n", *schedule, err)
+			return 2
+		}
+		next = cronSchedule.Next
+	}
+	if d.policy, err = retention.ParsePolicy(*retentionPolicy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	options.CollectionTime = time.Duration(*waitTime) * time.Second
+	options.ExcludeTypes = splitList(*excludeStr)
+	options.QuietMode = !options.VerboseMode
+	d.base = filepath.Base(options.OutputFile)
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer d.shutdown()
+
+	if *metricsAddr != "" {
+		listener, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving metrics: %v\n", err)
+			return 1
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go server.Serve(listener)
+		defer server.Close()
+		slog.Info("serving metrics", "url", fmt.Sprintf("http://%s/metrics", listener.Addr()))
+	}
+
+	mode := "restarting MeshSync per snapshot"
+	if d.keepWarm {
+		mode = "keeping MeshSync warm"
+	}
+	slog.Info("daemon started", "dir", d.dir, "mode", mode)
+	if len(d.policy) > 0 {
+		slog.Info("retention policy", "policy", d.policy.String())
+	}
+
+	runAt := time.Now()
+	if *interval == 0 {
+		runAt = next(runAt)
+	}
+	failures := 0
+	for {
+		slog.Info("next snapshot", "at", runAt.Format(time.RFC3339))
+		timer := time.NewTimer(time.Until(runAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.Info("daemon stopping")
+			return 0
+		case <-timer.C:
+		}
+
+		started := time.Now()
+		path, resources, err := d.capture(ctx)
+		if ctx.Err() != nil {
+			slog.Info("daemon stopping")
+			return 0
+		}
+		if err != nil {
+			failures++
+			metrics.SnapshotFailed()
+			slog.Error("snapshot failed", "error", err, "consecutive_failures", failures)
+			d.notify(webhookEvent{Event: "snapshot.failed", Time: time.Now(), Error: err.Error(), ConsecutiveFailures: failures})
+			if *maxFailures > 0 && failures >= *maxFailures {
+				slog.Error("giving up", "consecutive_failures", failures)
+				return 1
+			}
+		} else {
+			failures = 0
+			var size int64
+			if info, err := os.Stat(path); err == nil {
+				size = info.Size()
+			}
+			metrics.SnapshotSucceeded(resources, size, time.Since(started))
+			slog.Info("snapshot saved", "path", path, "resources", len(resources), "size", size)
+			if d.webhookOnSuccess {
+				d.notify(webhookEvent{Event: "snapshot.succeeded", Time: time.Now(), ClusterID: snapshot.ClusterID(resources), Path: path, Resources: len(resources)})
+			}
+			d.rotate()
+		}
+
+		// Runs that overran their slot are skipped rather than queued.
+		if runAt = next(runAt); runAt.Before(time.Now()) {
+			runAt = next(time.Now())
+		}
+	}
+}
+
+// capture takes one snapshot and saves it under a timestamped name.
+func (d *daemon) capture(ctx context.Context) (string, []*models.KubernetesResource, error) {
+	var resources []*models.KubernetesResource
+	if d.keepWarm {
+		if err := d.ensureWarm(ctx); err != nil {
+			return "", nil, err
+		}
+		resources = d.store.Resources(d.options)
+	} else {
+		session, err := startCaptureSession(d.options, nil)
+		if err != nil {
+			return "", nil, err
+		}
+		collectCtx, cancel := context.WithTimeout(ctx, d.options.CollectionTime+5*time.Second)
+		resources, err = meshsync.CollectResources(collectCtx, captureNATSURL, d.options)
+		cancel()
+		session.Close()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to collect resources: %w", err)
+		}
+	}
+	if len(resources) == 0 {
+		return "", nil, fmt.Errorf("no resources collected")
+	}
+
+	path, err := filepath.Abs(utils.GenerateTimestampedFilename(filepath.Join(d.dir, d.base)))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := snapshot.SaveToFile(resources, path, d.options); err != nil {
+		return "", nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	if !d.noCatalog {
+		if _, err := recordSnapshot(d.catalogPath, d.catalogResources, path, resources, d.options); err != nil {
+			slog.Warn("could not record snapshot in catalog", "error", err)
+		}
+	}
+	return path, resources, nil
+}
+
+// ensureWarm starts NATS, MeshSync and the store on first use, and again
+// whenever MeshSync has died since the last run.
+func (d *daemon) ensureWarm(ctx context.Context) error {
+	if d.session != nil && d.session.Alive() {
+		return nil
+	}
+	if d.session != nil {
+		slog.Warn("MeshSync is no longer running, restarting")
+		d.shutdown()
+	}
+
+	session, err := startCaptureSession(d.options, func() error {
+		store, err := meshsync.NewStore(captureNATSURL, d.options)
+		d.store = store
+		return err
+	})
+	if err != nil {
+		d.shutdown()
+		return err
+	}
+	d.session = session
+	if err := d.store.WaitForSync(ctx, d.options.CollectionTime+5*time.Second); err != nil {
+		d.shutdown()
+		return err
+	}
+	return nil
+}
+
+func (d *daemon) shutdown() {
+	if d.store != nil {
+		d.store.Close()
+		d.store = nil
+	}
+	if d.session != nil {
+		d.session.Close()
+		d.session = nil
+	}
+}
+
+// rotate applies the retention policy to the timestamped snapshots in the
+// output directory.
+func (d *daemon) rotate() {
+	if len(d.policy) == 0 {
+		return
+	}
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		slog.Warn("retention skipped", "error", err)
+		return
+	}
+	var paths []string
+	var times []time.Time
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if t, ok := utils.ParseTimestampedFilename(d.base, entry.Name()); ok {
+			paths = append(paths, filepath.Join(d.dir, entry.Name()))
+			times = append(times, t)
+		}
+	}
+
+	expired := d.policy.Expired(times)
+	if len(expired) == 0 {
+		return
+	}
+	var c *catalog.Catalog
+	if !d.noCatalog {
+		if c, err = openCatalog(d.catalogPath); err != nil {
+			slog.Warn("could not open catalog", "error", err)
+		} else {
+			defer c.Close()
+		}
+	}
+	for _, i := range expired {
+		path, _ := filepath.Abs(paths[i])
+		if err := removeSnapshotFiles(path); err != nil {
+			slog.Warn("could not remove snapshot", "path", path, "error", err)
+			continue
+		}
+		if c != nil {
+			if err := c.DeletePath(path); err != nil {
+				slog.Warn("could not remove catalog entry", "error", err)
+			}
+		}
+		slog.Info("removed snapshot", "path", path)
+	}
+}
+
+func (d *daemon) notify(event webhookEvent) {
+	if d.webhook == "" {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(d.webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("webhook failed", "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		slog.Warn("webhook failed", "status", strings.TrimSpace(resp.Status))
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/deploy"
+)
+
+func runDeployJob(args []string) int {
+	fs := newSubcommandFlagSet("deploy-job", "--image image (--pvc name | --upload s3://bucket/prefix) [--schedule cron | --once] [-o file]")
+	opts := deploy.JobOptions{}
+	fs.StringVar(&opts.Image, "image", "", "Image containing kubectl-meshsync_snapshot, kubectl and meshsync")
+	fs.StringVar(&opts.Name, "name", deploy.DefaultName, "Name of the ServiceAccount, RBAC objects and CronJob")
+	fs.StringVar(&opts.Namespace, "job-namespace", deploy.DefaultNamespace, "Namespace the job runs in (not meshery, which captures create and delete)")
+	fs.StringVar(&opts.Schedule, "schedule", deploy.DefaultSchedule, "CronJob schedule")
+	once := fs.Bool("once", false, "Generate a one-off Job instead of a CronJob")
+	fs.StringVar(&opts.PVC, "pvc", "", "Write snapshots and the catalog to this PersistentVolumeClaim")
+	fs.BoolVar(&opts.CreatePVC, "create-pvc", false, "Also generate the PersistentVolumeClaim")
+	fs.StringVar(&opts.PVCSize, "pvc-size", deploy.DefaultPVCSize, "Size of the generated PersistentVolumeClaim")
+	fs.StringVar(&opts.StorageClass, "storage-class", "", "Storage class of the generated PersistentVolumeClaim")
+	fs.StringVar(&opts.Upload, "upload", "", "Upload snapshots to s3://bucket/prefix instead of keeping them on a volume")
+	fs.StringVar(&opts.UploadKey, "upload-key", "", "Object key template for --upload")
+	fs.StringVar(&opts.S3.Endpoint, "s3-endpoint", "", "S3 endpoint for --upload")
+	fs.StringVar(&opts.S3.Region, "s3-region", "", "S3 region for --upload")
+	fs.BoolVar(&opts.S3.PathStyle, "s3-path-style", false, "Use path-style bucket addressing")
+	fs.StringVar(&opts.S3Secret, "s3-secret", "", "Secret with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for --upload")
+	captureArgs := fs.String("capture-args", "", "Extra capture flags, e.g. \"-n payments --fast\"")
+	output := fs.String("output", "", "Write manifests to this file instead of stdout")
+	fs.StringVar(output, "o", "", "Write manifests to this file instead of stdout (shorthand)")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *once {
+		opts.Schedule = ""
+	}
+	opts.CaptureArgs = strings.Fields(*captureArgs)
+
+	docs, err := deploy.JobManifests(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	data, err := deploy.WriteYAML(docs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifests: %v\n", err)
+		return 1
+	}
+	if err := writeOutput(*output, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifests: %v\n", err)
+		return 1
+	}
+	if *output != "" {
+		fmt.Printf("Manifests written to %s; apply them with kubectl apply -f %s\n", *output, *output)
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/export"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runExport(args []string) int {
+	fs := newSubcommandFlagSet("export", "--to design|manifests|kustomize|helm <snapshot> [-o output]")
+	target := fs.String("to", "", "Export target: design, manifests, kustomize or helm")
+	output := fs.String("output", "", "Output file for design (default stdout) or output directory for the other targets")
+	fs.StringVar(output, "o", "", "Output file or directory (shorthand)")
+	name := fs.String("name", "", "Design or chart name (default derived from the cluster ID or namespace)")
+	onlyOwnedRoots := fs.Bool("only-owned-roots", false, "Skip objects owned by a controller, such as ReplicaSets and Pods")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only export resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only export resources in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || *target == "" {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	snap.Resources = utils.FilterResources(snap.Resources, options)
+
+	switch *target {
+	case "design":
+		design := export.BuildDesign(snap, export.DesignOptions{Name: *name})
+		data, err := design.Marshal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting design: %v\n", err)
+			return 1
+		}
+		if err := writeOutput(*output, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing design: %v\n", err)
+			return 1
+		}
+		if *output != "" {
+			fmt.Printf("Design with %d components and %d relationships written to %s\n",
+				len(design.Components), len(design.Relationships), *output)
+		}
+	case "manifests":
+		dir := *output
+		if dir == "" {
+			dir = "manifests"
+		}
+		written, err := export.WriteManifests(snap.Resources, dir, export.ManifestOptions{OnlyOwnedRoots: *onlyOwnedRoots})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting manifests: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Wrote %d manifests to %s\n", written, dir)
+	case "kustomize", "helm":
+		if options.Namespace == "" {
+			fmt.Fprintf(os.Stderr, "Error: --namespace is required for --to %s\n", *target)
+			return 2
+		}
+		dir := *output
+		var count int
+		if *target == "kustomize" {
+			if dir == "" {
+				dir = options.Namespace + "-base"
+			}
+			count, err = export.WriteKustomizeBase(snap.Resources, options.Namespace, dir)
+		} else {
+			if dir == "" {
+				dir = options.Namespace + "-chart"
+			}
+			count, err = export.WriteHelmChart(snap.Resources, options.Namespace, *name, dir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", *target, err)
+			return 1
+		}
+		fmt.Printf("Wrote %s output for %d resources from namespace %s to %s\n", *target, count, options.Namespace, dir)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported export target %q\n", *target)
+		return 2
+	}
+
+	return 0
+}
+
+func writeOutput(path string, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
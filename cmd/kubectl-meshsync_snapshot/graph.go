@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+func runGraph(args []string) int {
+	fs := newSubcommandFlagSet("graph", "<snapshot> [--format dot|mermaid|graphml] [-n namespace] [-o output]")
+	format := fs.String("format", graph.FormatDOT, "Graph format: dot, mermaid or graphml")
+	output := fs.String("output", "", "Output file (default stdout)")
+	fs.StringVar(output, "o", "", "Output file (shorthand)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only include resources in this namespace (and the Nodes they run on)")
+	fs.StringVar(&options.Namespace, "n", "", "Only include resources in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	// Checked before the output file is created, so a typo does not
+	// leave an empty file behind.
+	if !graph.ValidFormat(*format) {
+		fmt.Fprintf(os.Stderr, "Error: unsupported graph format %q (use dot, mermaid or graphml)\n", *format)
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	g := graph.Build(snap.Resources).Scope(options.Namespace)
+
+	var w io.Writer = os.Stdout
+	if *output != "" && *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := graph.Write(w, g, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing graph: %v\n", err)
+		return 1
+	}
+
+	if *output != "" && *output != "-" {
+		fmt.Printf("Graph with %d nodes and %d edges written to %s\n", len(g.Nodes), len(g.Edges), *output)
+	}
+	return 0
+}
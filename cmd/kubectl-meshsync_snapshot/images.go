@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/inventory"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runImages(args []string) int {
+	fs := newSubcommandFlagSet("images", "<snapshot> [--format text|csv|json|cyclonedx] [-n namespace] [-o file]")
+	format := fs.String("format", inventory.FormatText, "Output format: text, csv, json or cyclonedx")
+	var output string
+	fs.StringVar(&output, "output", "", "Write to this file instead of stdout")
+	fs.StringVar(&output, "o", "", "Write to this file instead of stdout (shorthand)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only inventory images used in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only inventory images used in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	resources := snap.Resources
+	if options.Namespace != "" {
+		resources = utils.FilterResources(resources, options)
+	}
+
+	var buf bytes.Buffer
+	err = inventory.Write(&buf, *format, inventory.Images(resources), inventory.WriteOptions{
+		ClusterID: snap.ClusterID,
+		Timestamp: snap.Timestamp,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing image inventory: %v\n", err)
+		return 1
+	}
+	if err := writeOutput(output, buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing image inventory: %v\n", err)
+		return 1
+	}
+	return 0
+}
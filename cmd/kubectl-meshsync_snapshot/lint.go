@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/lint"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runLint(args []string) int {
+	fs := newSubcommandFlagSet("lint", "<snapshot> [--rules rules.yaml] [--format text|json|sarif]")
+	format := fs.String("format", lint.FormatText, "Output format: text, json or sarif")
+	rulesFiles := fs.String("rules", "", "Comma-separated custom rule files: YAML files of CEL rules or .rego policies")
+	disable := fs.String("disable", "", "Comma-separated rule IDs to skip")
+	failOn := fs.String("fail-on", lint.SeverityError, "Exit with status 1 when a finding is at least this severe (error, warning, note or none)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only lint resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only lint resources in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *failOn != "none" && !lint.ValidSeverity(*failOn) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --fail-on value %q\n", *failOn)
+		return 2
+	}
+
+	rules := lint.BuiltinRules()
+	for _, path := range splitList(*rulesFiles) {
+		custom, err := lint.LoadRules(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading rules: %v\n", err)
+			return 1
+		}
+		rules = append(rules, custom...)
+	}
+
+	disabled := make(map[string]bool)
+	for _, id := range splitList(*disable) {
+		disabled[id] = true
+	}
+	var enabled []lint.Rule
+	for _, rule := range rules {
+		if !disabled[rule.ID()] {
+			enabled = append(enabled, rule)
+		}
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	findings := lint.NewEngine(enabled...).Run(utils.FilterResources(snap.Resources, options))
+	if err := lint.Write(os.Stdout, *format, findings, enabled, positional[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing findings: %v\n", err)
+		return 1
+	}
+
+	if *failOn != "none" && lint.AtLeast(findings, *failOn) {
+		return 1
+	}
+	return 0
+}
@@ -4,25 +4,33 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
-	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/crds"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/export"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/logging"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/meshsync"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
-	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/nats"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/selectors"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/storage"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
-	natsd "github.com/nats-io/nats-server/v2/server"
 )
 
 func main() {
+	logging.Setup(os.Stderr, slog.LevelInfo, logging.FormatText)
+
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
 
 	options := models.NewDefaultOptions()
 
@@ -36,6 +44,13 @@ func main() {
 	flag.StringVar(&options.LabelSelector, "selector", options.LabelSelector, "Filter resources by label selector (e.g., app=nginx)")
 	flag.StringVar(&options.LabelSelector, "l", options.LabelSelector, "Filter resources by label selector (shorthand)")
 	flag.StringVar(&options.OutputFormat, "format", options.OutputFormat, "Output format: json or yaml (default \"json\")")
+	flag.StringVar(&options.SignKeyFile, "sign-key", options.SignKeyFile, "PEM-encoded ed25519 private key used to write a detached signature")
+	flag.StringVar(&options.AttributeFormat, "attribute-format", options.AttributeFormat, "How spec/status attributes are written: string (Meshery import form) or object (native JSON)")
+	flag.BoolVar(&options.DesignMetadata, "design-metadata", options.DesignMetadata, "Fill pattern_resource and component_metadata with the Meshery design component for each resource")
+	flag.BoolVar(&options.Canonical, "canonical", options.Canonical, "Write deterministic output: sorted resources, labels and annotations, volatile fields removed")
+	flag.BoolVar(&options.OmitTimestamps, "omit-timestamps", options.OmitTimestamps, "Leave per-run capture timestamps out of the snapshot header")
+	encryptTo := flag.String("encrypt-to", "", "Comma-separated age recipients (age1...) to encrypt the snapshot for")
+	flag.StringVar(&options.PassphraseFile, "passphrase-file", options.PassphraseFile, "Encrypt the snapshot with a passphrase read from this file")
 	flag.BoolVar(&options.FastMode, "fast", options.FastMode, "Capture only essential resources with shorter timeout")
 
 	waitTime := flag.Int("time", int(options.CollectionTime.Seconds()), "Collection time in seconds")
@@ -47,7 +62,39 @@ func main() {
 
 	excludeStr := flag.String("exclude", "", "Comma-separated list of resource types to exclude")
 
+	var s3Config storage.S3Config
+	upload := flag.String("upload", "", "Upload the saved snapshot to s3://bucket/prefix")
+	uploadKey := flag.String("upload-key", storage.DefaultKeyTemplate, "Object key template for --upload ({cluster_id}, {timestamp}, {date}, {filename})")
+	addS3Flags(flag.CommandLine, &s3Config)
+	mesheryURL := flag.String("meshery-url", os.Getenv("MESHERY_URL"), "Import the snapshot into this Meshery server as a design after saving")
+	catalogPath := addCatalogFlag(flag.CommandLine)
+	noCatalog := flag.Bool("no-catalog", false, "Do not record the snapshot in the catalog")
+	catalogResources := flag.Bool("catalog-resources", false, "Also store every resource in the catalog for SQL queries across snapshots")
+	mesheryToken := flag.String("token", os.Getenv("MESHERY_TOKEN"), "Meshery token, or a token file such as mesheryctl's auth.json")
+	logLevel, logFormat := addLogFlags(flag.CommandLine)
+	summaryFormat := flag.String("summary-format", "", "Print the result on stdout as json or yaml instead of the prose summary")
+
 	flag.Parse()
+	started := time.Now()
+
+	if err := setupLogging(*logLevel, *logFormat, options); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if !validSummaryFormat(*summaryFormat) {
+		fmt.Fprintf(os.Stderr, "Error: --summary-format must be json or yaml\n")
+		os.Exit(2)
+	}
+	summary := &captureSummary{Status: summaryFailed, Kinds: map[string]int{}, Namespaces: map[string]int{}}
+	fail := func(message string, err error) {
+		slog.Error(message, "error", err)
+		if *summaryFormat != "" {
+			summary.Status = summaryFailed
+			summary.Error = err.Error()
+			summary.write(*summaryFormat, started)
+		}
+		os.Exit(1)
+	}
 
 	if options.FastMode && *waitTime == 5 {
 		*waitTime = 3 
@@ -61,6 +108,28 @@ func main() {
 		}
 	}
 
+	if _, err := selectors.ParseLabelSelector(options.LabelSelector); err != nil {
+		fail("Invalid --selector", err)
+	}
+
+	var uploadLocation storage.Location
+	if *upload != "" {
+		location, err := storage.ParseS3URL(*upload)
+		if err != nil {
+			fail("Invalid --upload", err)
+		}
+		uploadLocation = location
+	}
+
+	if options.AttributeFormat != snapshot.AttributeFormatString && options.AttributeFormat != snapshot.AttributeFormatObject {
+		fail("Invalid --attribute-format", fmt.Errorf("invalid attribute format %q (use %s or %s)",
+			options.AttributeFormat, snapshot.AttributeFormatString, snapshot.AttributeFormatObject))
+	}
+
+	if *encryptTo != "" {
+		options.EncryptTo = splitList(*encryptTo)
+	}
+
 	if options.AutoName {
 		options.OutputFile = utils.GenerateTimestampedFilename(options.OutputFile)
 	}
@@ -73,144 +142,117 @@ func main() {
 
 	go func() {
 		<-sigChan
-		fmt.Println("\nInterrupted. Cleaning up...")
+		slog.Warn("Interrupted, cleaning up")
 		cancel() 
 	}()
 
-	if options.VerboseMode {
-		fmt.Println("Starting kubectl meshsync-snapshot...")
-	}
+	slog.Debug("Starting kubectl meshsync-snapshot")
 
 	if options.PreviewMode {
-		fmt.Println("Preview mode - showing what would be captured without actually running")
+		slog.Info("Preview mode: showing what would be captured without actually running")
 		previewResources, _ := meshsync.CollectResources(ctx, "", options)
 		utils.PrintResourceSummary(previewResources, options)
-		fmt.Println("Preview completed. No snapshot was created.")
+		slog.Info("Preview completed, no snapshot was created")
 		return
 	}
 
-	meshsyncPath, err := findMeshSyncBinary()
+	session, err := startCaptureSession(options, nil)
 	if err != nil {
-		fmt.Printf("Error finding MeshSync binary: %v\n", err)
-		os.Exit(1)
+		fail("Failed to start capture", err)
 	}
+	defer session.Close()
 
-	setupHostsEntry()
-
-	var wg sync.WaitGroup
-	var natsServer *natsd.Server
-	var natsErr error
-	var crdManager *crds.Manager
-	var crdErr error
-
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		natsServer, natsErr = nats.StartServer(options)
-	}()
-
-	go func() {
-		defer wg.Done()
-		crdManager = crds.NewManager("pkg/crds/meshery-crds.yaml", options)
-		crdErr = crdManager.Apply()
-	}()
-
-	wg.Wait()
-
-	if natsErr != nil {
-		fmt.Printf("Error starting NATS server: %v\n", natsErr)
-		os.Exit(1)
+	collection, err := meshsync.Collect(ctx, captureNATSURL, options)
+	// Collection is over either way; tear down before anything can exit.
+	session.Close()
+	if err != nil {
+		fail("Failed to collect resources", err)
 	}
-
-	if crdErr != nil {
-		fmt.Printf("Error applying CRDs: %v\n", crdErr)
-		if natsServer != nil {
-			natsServer.Shutdown()
-		}
-		os.Exit(1)
+	resources := collection.Resources
+	if collection.Status == meshsync.CollectionInterrupted {
+		slog.Warn("Collection was interrupted, the snapshot may be incomplete")
 	}
 
-	defer func() {
-		if natsServer != nil {
-			if options.VerboseMode {
-				fmt.Println("Shutting down NATS server...")
-			}
-			natsServer.Shutdown()
-		}
-	}()
-
-	meshSyncCmd, err := meshsync.Run("nats:4222", meshsyncPath, options)
+	absOutputPath, err := filepath.Abs(options.OutputFile)
 	if err != nil {
-		fmt.Printf("Error starting MeshSync: %v\n", err)
-
-		crdManager.Remove()
-		os.Exit(1)
+		absOutputPath = options.OutputFile
 	}
 
-	defer func() {
-		if meshSyncCmd != nil && meshSyncCmd.Process != nil {
-			if options.VerboseMode {
-				fmt.Println("Terminating MeshSync process...")
-			}
-
-			meshsync.KillProcessGroup(meshSyncCmd)
-
-			exec.Command("pkill", "-9", "-f", "meshsync").Run()
-
-			time.Sleep(200 * time.Millisecond)
-		}
-
-		crdManager.Remove()
-
-		if err:= deleteNamespace("meshery"); err != nil {
-			fmt.Printf("Error deleting namespace meshery: %v\n", err)
-		} else {
-			fmt.Println("Namespace meshery deleted successfully.")
-		}
+	if err := utils.CreateParentDirs(absOutputPath); err != nil {
+		slog.Debug("Could not create parent directories", "error", err)
+	}
 
+	slog.Info("Saving snapshot", "path", absOutputPath)
 
-	}()
+	if options.DesignMetadata {
+		export.FillDesignMetadata(&models.Snapshot{ClusterID: snapshot.ClusterID(resources), Resources: resources}, export.DesignOptions{})
+	}
 
-	resources, err := meshsync.CollectResources(ctx, "nats://localhost:4222", options)
+	saved, err := snapshot.SaveSnapshot(resources, absOutputPath, options)
 	if err != nil {
-		fmt.Printf("Error collecting resources: %v\n", err)
-		os.Exit(1)
+		fail("Failed to save snapshot", err)
 	}
+	summary.Status = collection.Status
+	summary.Path = absOutputPath
+	summary.setResources(resources)
 
-	absOutputPath, err := filepath.Abs(options.OutputFile)
-	if err != nil {
-		absOutputPath = options.OutputFile
+	if fileInfo, err := os.Stat(absOutputPath); err != nil {
+		slog.Warn("Could not confirm file was created", "error", err)
+	} else {
+		summary.Size = fileInfo.Size()
+		slog.Info("Snapshot saved", "size", utils.FormatSize(fileInfo.Size()))
 	}
 
-	if err := utils.CreateParentDirs(absOutputPath); err != nil && options.VerboseMode {
-		fmt.Printf("Warning: Could not create parent directories: %v\n", err)
+	if !*noCatalog {
+		id, err := recordSnapshot(*catalogPath, *catalogResources, absOutputPath, resources, options)
+		if err != nil {
+			slog.Warn("Could not record snapshot in catalog", "error", err)
+		} else {
+			summary.CatalogID = id
+			slog.Debug("Recorded in catalog", "id", id)
+		}
 	}
 
-	if !options.QuietMode {
-		fmt.Printf("Saving snapshot to %s...\n", absOutputPath)
+	prose := !options.QuietMode && *summaryFormat == ""
+	if *summaryFormat == "" {
+		utils.PrintResourceSummary(resources, options)
 	}
 
-	if err := snapshot.SaveToFile(resources, absOutputPath, options); err != nil {
-		fmt.Printf("Error saving snapshot: %v\n", err)
-		os.Exit(1)
+	if prose {
+		fmt.Printf("Snapshot created successfully with %d resources\n", len(resources))
+		if *mesheryURL == "" {
+			fmt.Printf("You can now import this snapshot into Meshery\n")
+		}
+		fmt.Printf("Snapshot saved to: %s\n", absOutputPath)
 	}
 
-	if _, err := os.Stat(absOutputPath); err != nil {
-		fmt.Printf("Warning: Could not confirm file was created: %v\n", err)
-	} else {
-		fileInfo, err := os.Stat(absOutputPath)
-		if err == nil && !options.QuietMode {
-			fmt.Printf("Snapshot file size: %s\n", utils.FormatSize(fileInfo.Size()))
+	if *upload != "" {
+		url, err := uploadSnapshot(s3Config, uploadLocation, *uploadKey, absOutputPath, snapshot.ClusterID(resources))
+		if err != nil {
+			fail("Failed to upload snapshot", err)
+		}
+		summary.Upload = url
+		if prose {
+			fmt.Printf("Snapshot uploaded to: %s\n", url)
 		}
 	}
 
-	utils.PrintResourceSummary(resources, options)
+	if *mesheryURL != "" {
+		result, err := pushToMeshery(saved, *mesheryURL, *mesheryToken, "", options.QuietMode)
+		if err != nil {
+			fail("Failed to push to Meshery", err)
+		}
+		summary.MesheryDesignID = result.ID
+		if *summaryFormat == "" {
+			printImportResult(result, options.QuietMode)
+		}
+	}
 
-	if !options.QuietMode {
-		fmt.Printf("Snapshot created successfully with %d resources\n", len(resources))
-		fmt.Printf("You can now import this snapshot into Meshery\n")
-		fmt.Printf("Snapshot saved to: %s\n", absOutputPath)
+	if *summaryFormat != "" {
+		if err := summary.write(*summaryFormat, started); err != nil {
+			slog.Error("Failed to write summary", "error", err)
+			os.Exit(1)
+		}
 	}
 }
 
@@ -253,6 +295,6 @@ func deleteNamespace(namespace string) error {
 		return fmt.Errorf("failed to delete namespace %s: %v\nOutput: %s", namespace, err, string(output))
 	}
 
-	fmt.Printf("Namespace %s deleted successfully.\nOutput: %s", namespace, string(output))
+	slog.Debug("kubectl delete namespace", "output", strings.TrimSpace(string(output)))
 	return nil
 }
\ No newline at end of file
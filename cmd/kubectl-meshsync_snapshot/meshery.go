@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/export"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/meshery"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+// pushToMeshery converts the snapshot to a design and imports it through
+// the Meshery server's design import API.
+func pushToMeshery(snap *models.Snapshot, serverURL, tokenValue, name string, quiet bool) (*meshery.ImportResult, error) {
+	token, provider, err := meshery.LoadToken(tokenValue)
+	if err != nil {
+		return nil, err
+	}
+	client := meshery.NewClient(serverURL, token)
+	client.Provider = provider
+
+	design := export.BuildDesign(snap, export.DesignOptions{Name: name})
+	data, err := design.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build design: %w", err)
+	}
+	if !quiet {
+		slog.Info("Importing design", "name", design.Name, "components", len(design.Components), "server", client.BaseURL)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return client.ImportDesign(ctx, design.Name, design.Name+".json", data)
+}
+
+func printImportResult(result *meshery.ImportResult, quiet bool) {
+	switch {
+	case result.ID != "":
+		fmt.Printf("Meshery design created: %s\n", result.ID)
+	case !quiet:
+		fmt.Printf("Meshery accepted the design but did not report its ID\n")
+	}
+	if result.ConnectionID != "" {
+		fmt.Printf("Meshery connection: %s\n", result.ConnectionID)
+	}
+}
+
+func runPush(args []string) int {
+	fs := newSubcommandFlagSet("push", "<snapshot> --meshery-url url [--token token|file] [--name design]")
+	serverURL := fs.String("meshery-url", os.Getenv("MESHERY_URL"), "Meshery server URL, e.g. http://localhost:9081")
+	token := fs.String("token", os.Getenv("MESHERY_TOKEN"), "Meshery token, or a token file such as mesheryctl's auth.json")
+	name := fs.String("name", "", "Design name (default derived from the cluster ID)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only push resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only push resources in this namespace (shorthand)")
+	fs.BoolVar(&options.QuietMode, "quiet", false, "Only print the created design ID")
+	fs.BoolVar(&options.QuietMode, "q", false, "Only print the created design ID (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || *serverURL == "" {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+	snap.Resources = utils.FilterResources(snap.Resources, options)
+
+	result, err := pushToMeshery(snap, *serverURL, *token, *name, options.QuietMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error pushing to Meshery: %v\n", err)
+		return 1
+	}
+	printImportResult(result, options.QuietMode)
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/lint"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runOrphans(args []string) int {
+	fs := newSubcommandFlagSet("orphans", "<snapshot> [--format text|json|sarif] [-n namespace]")
+	format := fs.String("format", lint.FormatText, "Output format: text, json or sarif")
+	failOn := fs.String("fail-on", lint.SeverityError, "Exit with status 1 when a finding is at least this severe (error, warning, note or none)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only check resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only check resources in this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *failOn != "none" && !lint.ValidSeverity(*failOn) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --fail-on value %q\n", *failOn)
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	rules := lint.ReferenceRules()
+	findings := lint.NewEngine(rules...).RunScoped(snap.Resources, utils.FilterResources(snap.Resources, options))
+	if err := lint.Write(os.Stdout, *format, findings, rules, positional[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing findings: %v\n", err)
+		return 1
+	}
+
+	if *failOn != "none" && lint.AtLeast(findings, *failOn) {
+		return 1
+	}
+	return 0
+}
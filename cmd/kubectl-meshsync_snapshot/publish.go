@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/meshsync"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	natsserver "github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/nats"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+	"github.com/nats-io/nats.go"
+)
+
+func runPublish(args []string) int {
+	fs := newSubcommandFlagSet("publish", "<snapshot> [--nats-url nats://host:4222 | --start-server] [--subject meshery.meshsync.core]")
+	natsURL := fs.String("nats-url", "nats://localhost:4222", "NATS broker to publish to")
+	subject := fs.String("subject", meshsync.DefaultSubject, "Subject to publish ADDED events on")
+	startServer := fs.Bool("start-server", false, "Start a local NATS broker on port 4222, publish once a subscriber connects, then keep serving until interrupted")
+	interval := fs.Duration("interval", 0, "Delay between messages (e.g. 5ms)")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only publish resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only publish resources in this namespace (shorthand)")
+	fs.StringVar(&options.ResourceType, "type", "", "Only publish resources of this type")
+	fs.StringVar(&options.ResourceType, "t", "", "Only publish resources of this type (shorthand)")
+	fs.StringVar(&options.LabelSelector, "selector", "", "Only publish resources matching this label selector")
+	fs.StringVar(&options.LabelSelector, "l", "", "Only publish resources matching this label selector (shorthand)")
+	fs.BoolVar(&options.VerboseMode, "verbose", false, "Detailed output")
+	fs.BoolVar(&options.VerboseMode, "v", false, "Detailed output (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+	resources := utils.FilterResources(snap.Resources, options)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	url := *natsURL
+	if *startServer {
+		server, err := natsserver.StartServer(options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting NATS server: %v\n", err)
+			return 1
+		}
+		defer server.Shutdown()
+		url = server.ClientURL()
+
+		// Core NATS does not retain messages, so publishing before anyone
+		// subscribes would drop every event.
+		fmt.Printf("NATS broker listening on %s; waiting for a subscriber on %s...\n", url, *subject)
+		account := server.GlobalAccount()
+		for !account.SubscriptionInterest(*subject) {
+			select {
+			case <-ctx.Done():
+				return 1
+			case <-time.After(200 * time.Millisecond):
+			}
+		}
+	}
+
+	nc, err := nats.Connect(url, nats.Timeout(5*time.Second))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error connecting to NATS at %s: %v\n", url, err)
+		return 1
+	}
+	defer nc.Close()
+
+	published, err := meshsync.PublishResources(nc, resources, meshsync.PublishOptions{
+		Subject:  *subject,
+		Interval: *interval,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (published %d of %d)\n", err, published, len(resources))
+		return 1
+	}
+	fmt.Printf("Published %d resources to %s on %s\n", published, *subject, url)
+
+	if *startServer {
+		fmt.Println("Keeping the broker up so subscribers can finish reading. Press Ctrl+C to stop.")
+		<-ctx.Done()
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/query"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+func runQuery(args []string) int {
+	fs := newSubcommandFlagSet("query", "<snapshot> [-e \"get pods -n default\"]")
+	expression := fs.String("e", "", "Run a single command and exit instead of starting the shell")
+	options := models.NewDefaultOptions()
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+	session := query.NewSession(snap, os.Stdout)
+
+	if *expression != "" {
+		if err := session.Execute(*expression); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	interactive := false
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		interactive = true
+		fmt.Printf("Loaded %d resources from cluster %s. Type \"help\" for commands.\n", len(snap.Resources), snap.ClusterID)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	failed := false
+	for {
+		if interactive {
+			fmt.Print("snapshot> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "exit" || line == "quit" {
+			break
+		}
+		if err := session.Execute(line); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+		}
+	}
+	if interactive {
+		fmt.Println()
+	}
+
+	if failed && !interactive {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/health"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runReport(args []string) int {
+	fs := newSubcommandFlagSet("report", "<snapshot> [--format text|json|markdown] [-n namespace]")
+	format := fs.String("format", health.ReportFormatText, "Report format: text, json or markdown")
+	restartThreshold := fs.Int64("restart-threshold", 5, "Report containers restarted at least this many times")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only report on this namespace (Nodes are always included)")
+	fs.StringVar(&options.Namespace, "n", "", "Only report on this namespace (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	if options.Namespace != "" {
+		var scoped []*models.KubernetesResource
+		for _, resource := range snap.Resources {
+			if resource != nil && resource.Kind == "Node" {
+				scoped = append(scoped, resource)
+			}
+		}
+		snap.Resources = append(scoped, utils.FilterResources(snap.Resources, options)...)
+	}
+
+	report := health.BuildReport(snap, health.ReportOptions{RestartThreshold: *restartThreshold})
+	if err := report.Write(os.Stdout, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return 1
+	}
+	return 0
+}
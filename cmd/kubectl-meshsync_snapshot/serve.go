@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/apiserver"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+func runServe(args []string) int {
+	fs := newSubcommandFlagSet("serve", "<snapshot> [--addr 127.0.0.1:8001] [--kubeconfig snapshot.kubeconfig]")
+	addr := fs.String("addr", "127.0.0.1:8001", "Address to listen on (use port 0 for a free port)")
+	kubeconfig := fs.String("kubeconfig", "snapshot.kubeconfig", "Kubeconfig file to write for the served snapshot")
+	options := models.NewDefaultOptions()
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listening on %s: %v\n", *addr, err)
+		return 1
+	}
+	server := "http://" + listener.Addr().String()
+
+	contextName := "meshsync-snapshot"
+	if snap.ClusterID != "" {
+		contextName += "-" + snap.ClusterID[:min(8, len(snap.ClusterID))]
+	}
+	if err := apiserver.WriteKubeconfig(*kubeconfig, server, contextName); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		listener.Close()
+		return 1
+	}
+	kubeconfigPath, _ := filepath.Abs(*kubeconfig)
+
+	fmt.Printf("Serving %d resources from %s at %s (read-only)\n", len(snap.Resources), positional[0], server)
+	fmt.Printf("Kubeconfig written to %s\n", kubeconfigPath)
+	fmt.Printf("  export KUBECONFIG=%s\n", kubeconfigPath)
+	fmt.Println("Press Ctrl+C to stop.")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	httpServer := &http.Server{
+		Handler: apiserver.New(snap),
+		// Open watches end with the server instead of holding up shutdown.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error serving snapshot: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/storage"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+// uploadSnapshot uploads a saved snapshot and returns its s3:// URL.
+func uploadSnapshot(cfg storage.S3Config, loc storage.Location, keyTemplate, localPath, clusterID string) (string, error) {
+	client, err := storage.NewS3Client(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	key := storage.ExpandKey(keyTemplate, clusterID, localPath, now)
+	slog.Info("Uploading snapshot", "location", loc.String(), "key", key)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fullKey, err := client.Upload(ctx, loc, key, localPath, map[string]string{
+		"cluster-id": clusterID,
+		"timestamp":  now.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", loc.Bucket, fullKey), nil
+}
+
+func runList(args []string) int {
+	fs := newSubcommandFlagSet("list", "s3://bucket/prefix [--cluster id] [--since time] [--until time] [--format table|json]")
+	var s3Config storage.S3Config
+	addS3Flags(fs, &s3Config)
+	cluster := fs.String("cluster", "", "Only list snapshots of this cluster ID")
+	since := fs.String("since", "", "Only list snapshots stored at or after this time (RFC 3339, date, or a duration such as 24h)")
+	until := fs.String("until", "", "Only list snapshots stored at or before this time (RFC 3339, date, or a duration such as 24h)")
+	format := fs.String("format", "table", "Output format: table or json")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || (*format != "table" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	loc, err := storage.ParseS3URL(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	listOptions := storage.ListOptions{Cluster: *cluster}
+	if listOptions.Since, err = parseTimeBound(*since); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+		return 2
+	}
+	if listOptions.Until, err = parseTimeBound(*until); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --until: %v\n", err)
+		return 2
+	}
+
+	client, err := storage.NewS3Client(s3Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	objects, err := client.List(context.Background(), loc, listOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing snapshots: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		if objects == nil {
+			objects = []storage.Object{}
+		}
+		return printJSON(objects)
+	}
+
+	if len(objects) == 0 {
+		fmt.Printf("No snapshots found under %s\n", loc)
+		return 0
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORED\tSIZE\tLOCATION")
+	for _, object := range objects {
+		fmt.Fprintf(tw, "%s\t%s\ts3://%s/%s\n", object.LastModified.UTC().Format(time.RFC3339), utils.FormatSize(object.Size), loc.Bucket, object.Key)
+	}
+	tw.Flush()
+	return 0
+}
+
+func runFetch(args []string) int {
+	fs := newSubcommandFlagSet("fetch", "s3://bucket/key | s3://bucket/prefix --latest [--cluster id] [-o file]")
+	var s3Config storage.S3Config
+	addS3Flags(fs, &s3Config)
+	latest := fs.Bool("latest", false, "Fetch the most recently stored snapshot under the prefix")
+	cluster := fs.String("cluster", "", "With --latest, only consider snapshots of this cluster ID")
+	var output string
+	fs.StringVar(&output, "output", "", "Local file to write (default: the object's file name without .gz)")
+	fs.StringVar(&output, "o", "", "Local file to write (shorthand)")
+
+	positional, err := parseSubcommand(fs, args, nil)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 || (*cluster != "" && !*latest) {
+		fs.Usage()
+		return 2
+	}
+
+	loc, err := storage.ParseS3URL(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	client, err := storage.NewS3Client(s3Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	key := loc.Prefix
+	if *latest {
+		objects, err := client.List(ctx, loc, storage.ListOptions{Cluster: *cluster})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing snapshots: %v\n", err)
+			return 1
+		}
+		if len(objects) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no snapshots found under %s\n", loc)
+			return 1
+		}
+		key = objects[0].Key
+	}
+	if key == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s names a bucket, not a snapshot (use --latest to pick one)\n", loc)
+		return 2
+	}
+
+	if output == "" {
+		output = strings.TrimSuffix(path.Base(key), ".gz")
+	}
+	if err := client.Fetch(ctx, loc.Bucket, key, output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Fetched s3://%s/%s to %s\n", loc.Bucket, key, output)
+	return 0
+}
+
+// parseTimeBound accepts an RFC 3339 time, a date, or a duration counted
+// back from now.
+func parseTimeBound(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, YYYY-MM-DD or a duration such as 24h)", value)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/logging"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+const summaryFailed = "failed"
+
+// captureSummary is the machine-readable result --summary-format prints
+// on stdout in place of the prose summary.
+type captureSummary struct {
+	Status          string         `json:"status" yaml:"status"`
+	Error           string         `json:"error,omitempty" yaml:"error,omitempty"`
+	Path            string         `json:"path,omitempty" yaml:"path,omitempty"`
+	Size            int64          `json:"size" yaml:"size"`
+	ClusterID       string         `json:"cluster_id,omitempty" yaml:"cluster_id,omitempty"`
+	Resources       int            `json:"resources" yaml:"resources"`
+	Kinds           map[string]int `json:"kinds" yaml:"kinds"`
+	Namespaces      map[string]int `json:"namespaces" yaml:"namespaces"`
+	DurationSeconds float64        `json:"duration_seconds" yaml:"duration_seconds"`
+	CatalogID       int64          `json:"catalog_id,omitempty" yaml:"catalog_id,omitempty"`
+	Upload          string         `json:"upload,omitempty" yaml:"upload,omitempty"`
+	MesheryDesignID string         `json:"meshery_design_id,omitempty" yaml:"meshery_design_id,omitempty"`
+	Warnings        []string       `json:"warnings" yaml:"warnings"`
+}
+
+func validSummaryFormat(format string) bool {
+	return format == "" || format == "json" || format == "yaml"
+}
+
+// setResources fills the cluster ID and the per-kind and per-namespace
+// counts. Cluster-scoped resources only appear under kinds.
+func (s *captureSummary) setResources(resources []*models.KubernetesResource) {
+	s.ClusterID = snapshot.ClusterID(resources)
+	s.Resources = len(resources)
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		s.Kinds[resource.Kind]++
+		if resource.KubernetesResourceMeta != nil && resource.KubernetesResourceMeta.Namespace != "" {
+			s.Namespaces[resource.KubernetesResourceMeta.Namespace]++
+		}
+	}
+}
+
+// write prints the summary with the run's duration and warnings.
+func (s *captureSummary) write(format string, started time.Time) error {
+	s.DurationSeconds = float64(time.Since(started).Milliseconds()) / 1000
+	s.Warnings = logging.Warnings()
+
+	var data []byte
+	var err error
+	if format == "yaml" {
+		data, err = yaml.Marshal(s)
+	} else {
+		data, err = json.MarshalIndent(s, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
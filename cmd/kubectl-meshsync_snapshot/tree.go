@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/health"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+func runTree(args []string) int {
+	fs := newSubcommandFlagSet("tree", "<snapshot> [-n namespace] [--type kind] [--orphans]")
+	orphansOnly := fs.Bool("orphans", false, "Only show resources whose owner is missing from the snapshot")
+	options := models.NewDefaultOptions()
+	fs.StringVar(&options.Namespace, "namespace", "", "Only show resources in this namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Only show resources in this namespace (shorthand)")
+	fs.StringVar(&options.ResourceType, "type", "", "Only show trees rooted at this resource type")
+	fs.StringVar(&options.ResourceType, "t", "", "Only show trees rooted at this resource type (shorthand)")
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	snap, err := snapshot.LoadFromFile(positional[0], options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	rootType := options.ResourceType
+	options.ResourceType = ""
+	scope := utils.FilterResources(snap.Resources, options)
+	roots := graph.OwnerForest(snap.Resources, scope)
+
+	if rootType != "" {
+		options.ResourceType = rootType
+		var filtered []*graph.TreeNode
+		for _, root := range roots {
+			if len(utils.FilterResources([]*models.KubernetesResource{root.Resource}, options)) > 0 {
+				filtered = append(filtered, root)
+			}
+		}
+		roots = filtered
+	}
+
+	orphans := 0
+	currentNamespace := "\x00"
+	for _, root := range roots {
+		if *orphansOnly && !root.IsOrphan() {
+			continue
+		}
+		if ns := root.Resource.Namespace(); ns != currentNamespace {
+			currentNamespace = ns
+			if ns == "" {
+				ns = "(cluster-scoped)"
+			}
+			fmt.Printf("\n%s\n", ns)
+		}
+		orphans += printTreeNode(root, "", "")
+	}
+
+	if orphans > 0 {
+		fmt.Printf("\n%d orphaned resource(s) reference owners that are not in the snapshot\n", orphans)
+	}
+	return 0
+}
+
+func printTreeNode(node *graph.TreeNode, prefix, childPrefix string) int {
+	resource := node.Resource
+	status := health.Evaluate(resource)
+
+	line := fmt.Sprintf("%s/%s", resource.Kind, resource.Name())
+	detail := status.Glyph()
+	if status.Detail != "" {
+		detail += " " + status.Detail
+	}
+
+	orphans := 0
+	if node.IsOrphan() {
+		var owners []string
+		for _, ref := range node.MissingOwners {
+			owners = append(owners, fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+		}
+		detail += fmt.Sprintf("  ORPHAN (owner %s not in snapshot)", strings.Join(owners, ", "))
+		orphans++
+	}
+
+	fmt.Printf("%s%s  [%s]\n", prefix, line, detail)
+
+	for i, child := range node.Children {
+		last := i == len(node.Children)-1
+		branch, next := "├── ", "│   "
+		if last {
+			branch, next = "└── ", "    "
+		}
+		orphans += printTreeNode(child, childPrefix+branch, childPrefix+next)
+	}
+	return orphans
+}
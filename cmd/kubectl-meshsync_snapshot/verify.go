@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/snapshot"
+)
+
+func runVerify(args []string) int {
+	fs := newSubcommandFlagSet("verify", "<file> [--pub-key key.pem] [--sig file.sig]")
+	pubKey := fs.String("pub-key", "", "PEM-encoded ed25519 public key used to check the detached signature")
+	sigFile := fs.String("sig", "", "Detached signature file (default \"<file>.sig\")")
+	options := models.NewDefaultOptions()
+	addDecryptionFlags(fs, options)
+
+	positional, err := parseSubcommand(fs, args, options)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	snapshotPath := positional[0]
+
+	snap, err := snapshot.LoadFromFile(snapshotPath, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading snapshot: %v\n", err)
+		return 1
+	}
+
+	if snap.SHA256 == "" {
+		fmt.Fprintf(os.Stderr, "Snapshot %s has no sha256 digest in its header\n", snapshotPath)
+		return 1
+	}
+
+	digest, err := snapshot.ComputeDigest(snap.Resources)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing digest: %v\n", err)
+		return 1
+	}
+
+	if digest != snap.SHA256 {
+		fmt.Fprintf(os.Stderr, "Digest mismatch: header has %s, resources hash to %s\n", snap.SHA256, digest)
+		return 1
+	}
+	fmt.Printf("Digest OK: sha256:%s\n", digest)
+
+	if *pubKey == "" {
+		return 0
+	}
+
+	if *sigFile == "" {
+		*sigFile = snapshotPath + snapshot.SignatureExtension
+	}
+
+	signature, err := os.ReadFile(*sigFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading signature: %v\n", err)
+		return 1
+	}
+
+	if err := snapshot.VerifySignature(snap, signature, *pubKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Signature verification failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Signature OK: %s\n", *sigFile)
+	return 0
+}
@@ -0,0 +1,154 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/query"
+)
+
+// groupVersions returns every API group in the snapshot with its versions.
+// The core group is served under /api and is left out.
+func (s *Server) groupVersions() map[string][]string {
+	groups := make(map[string][]string)
+	for _, t := range s.types {
+		if t.Group == "" {
+			continue
+		}
+		if !containsString(groups[t.Group], t.Version) {
+			groups[t.Group] = append(groups[t.Group], t.Version)
+		}
+	}
+	for _, versions := range groups {
+		sort.Slice(versions, func(i, j int) bool {
+			return versionPriority(versions[i], versions[j])
+		})
+	}
+	return groups
+}
+
+var (
+	kubeVersion = regexp.MustCompile(`^v([0-9]+)(?:(alpha|beta)([0-9]+))?$`)
+	stability   = map[string]int{"": 2, "beta": 1, "alpha": 0}
+)
+
+// versionPriority orders API versions the way the API server lists them:
+// GA before beta before alpha, higher numbers first within each, and
+// versions that do not follow the vNbetaM pattern last, alphabetically.
+func versionPriority(a, b string) bool {
+	ma, mb := kubeVersion.FindStringSubmatch(a), kubeVersion.FindStringSubmatch(b)
+	switch {
+	case ma == nil && mb == nil:
+		return a < b
+	case ma == nil || mb == nil:
+		return ma != nil
+	}
+	if ma[2] != mb[2] {
+		return stability[ma[2]] > stability[mb[2]]
+	}
+	majorA, _ := strconv.Atoi(ma[1])
+	majorB, _ := strconv.Atoi(mb[1])
+	if majorA != majorB {
+		return majorA > majorB
+	}
+	minorA, _ := strconv.Atoi(ma[3])
+	minorB, _ := strconv.Atoi(mb[3])
+	return minorA > minorB
+}
+
+func groupDocument(name string, versions []string) map[string]interface{} {
+	var entries []map[string]string
+	for _, version := range versions {
+		entries = append(entries, map[string]string{"groupVersion": name + "/" + version, "version": version})
+	}
+	return map[string]interface{}{
+		"name":             name,
+		"versions":         entries,
+		"preferredVersion": entries[0],
+	}
+}
+
+func (s *Server) serveGroups(w http.ResponseWriter) {
+	groups := s.groupVersions()
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	documents := []map[string]interface{}{}
+	for _, name := range names {
+		documents = append(documents, groupDocument(name, groups[name]))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":       "APIGroupList",
+		"apiVersion": "v1",
+		"groups":     documents,
+	})
+}
+
+func (s *Server) serveGroup(w http.ResponseWriter, name string) {
+	versions, ok := s.groupVersions()[name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("API group %q is not in the snapshot", name))
+		return
+	}
+	document := groupDocument(name, versions)
+	document["kind"] = "APIGroup"
+	document["apiVersion"] = "v1"
+	writeJSON(w, http.StatusOK, document)
+}
+
+func (s *Server) serveResourceList(w http.ResponseWriter, group, version string) {
+	resources := []map[string]interface{}{}
+	for _, t := range s.types {
+		if t.Group != group || t.Version != version {
+			continue
+		}
+		resource := map[string]interface{}{
+			"name":         t.Plural,
+			"singularName": strings.ToLower(t.Kind),
+			"namespaced":   t.Namespaced,
+			"kind":         t.Kind,
+			"verbs":        []string{"get", "list", "watch"},
+		}
+		if shortNames := query.ShortNames(t.Kind); len(shortNames) > 0 {
+			resource["shortNames"] = shortNames
+		}
+		resources = append(resources, resource)
+	}
+	if len(resources) == 0 {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s is not in the snapshot", joinGroupVersion(group, version)))
+		return
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i]["name"].(string) < resources[j]["name"].(string)
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":         "APIResourceList",
+		"apiVersion":   "v1",
+		"groupVersion": joinGroupVersion(group, version),
+		"resources":    resources,
+	})
+}
+
+func joinGroupVersion(group, version string) string {
+	if group == "" {
+		return version
+	}
+	return group + "/" + version
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
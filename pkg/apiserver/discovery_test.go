@@ -0,0 +1,35 @@
+package apiserver
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestVersionPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     []string
+	}{
+		{name: "GA before beta", versions: []string{"v1beta1", "v1"}, want: []string{"v1", "v1beta1"}},
+		{name: "GA numbers compared numerically", versions: []string{"v10", "v2", "v1"}, want: []string{"v10", "v2", "v1"}},
+		{name: "older GA before newer beta", versions: []string{"v2beta1", "v1"}, want: []string{"v1", "v2beta1"}},
+		{name: "beta before alpha", versions: []string{"v1alpha1", "v1beta1", "v2alpha1"}, want: []string{"v1beta1", "v2alpha1", "v1alpha1"}},
+		{name: "beta numbers", versions: []string{"v2beta1", "v2beta2", "v1beta3"}, want: []string{"v2beta2", "v2beta1", "v1beta3"}},
+		{name: "non-kube versions last", versions: []string{"foo", "v1alpha1", "bar", "v1"}, want: []string{"v1", "v1alpha1", "bar", "foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions := append([]string{}, tt.versions...)
+			sort.Slice(versions, func(i, j int) bool { return versionPriority(versions[i], versions[j]) })
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("sorted %v = %v, want %v", tt.versions, versions, tt.want)
+			}
+			preferred := groupDocument("example.io", versions)["preferredVersion"].(map[string]string)
+			if preferred["version"] != tt.want[0] {
+				t.Errorf("preferred version = %s, want %s", preferred["version"], tt.want[0])
+			}
+		})
+	}
+}
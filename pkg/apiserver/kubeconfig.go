@@ -0,0 +1,45 @@
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteKubeconfig writes a kubeconfig with a single context pointing at the
+// snapshot server. The server is plain HTTP without authentication.
+func WriteKubeconfig(path, server, name string) error {
+	config := map[string]interface{}{
+		"apiVersion":      "v1",
+		"kind":            "Config",
+		"current-context": name,
+		"clusters": []map[string]interface{}{{
+			"name":    name,
+			"cluster": map[string]string{"server": server},
+		}},
+		"users": []map[string]interface{}{{
+			"name": name,
+			"user": map[string]string{},
+		}},
+		"contexts": []map[string]interface{}{{
+			"name": name,
+			"context": map[string]string{
+				"cluster": name,
+				"user":    name,
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("failed to marshal kubeconfig: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
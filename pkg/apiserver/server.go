@@ -0,0 +1,318 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/export"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/query"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/selectors"
+)
+
+// snapshotResourceVersion is reported for every list; a snapshot never
+// changes, so one version is enough for informers to settle.
+const snapshotResourceVersion = "1"
+
+type resourceType struct {
+	Group      string
+	Version    string
+	Kind       string
+	Plural     string
+	Namespaced bool
+	Resources  []*models.KubernetesResource
+}
+
+// Server answers the read paths of the Kubernetes API from a snapshot:
+// discovery, list and get for every captured kind, label and field
+// selectors, and watches that never deliver an event.
+type Server struct {
+	snapshot *models.Snapshot
+	session  *query.Session
+	types    map[string]*resourceType
+}
+
+func New(snap *models.Snapshot) *Server {
+	s := &Server{
+		snapshot: snap,
+		session:  query.NewSession(snap, io.Discard),
+		types:    make(map[string]*resourceType),
+	}
+	for _, resource := range snap.Resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil || resource.APIVersion == "" {
+			continue
+		}
+		group, version := "", resource.APIVersion
+		if idx := strings.Index(resource.APIVersion, "/"); idx >= 0 {
+			group, version = resource.APIVersion[:idx], resource.APIVersion[idx+1:]
+		}
+		plural := query.Plural(resource.Kind)
+		key := typeKey(group, version, plural)
+		t, ok := s.types[key]
+		if !ok {
+			t = &resourceType{Group: group, Version: version, Kind: resource.Kind, Plural: plural}
+			s.types[key] = t
+		}
+		if resource.Namespace() != "" {
+			t.Namespaced = true
+		}
+		t.Resources = append(t.Resources, resource)
+	}
+	for _, t := range s.types {
+		sort.SliceStable(t.Resources, func(i, j int) bool {
+			a, b := t.Resources[i], t.Resources[j]
+			if a.Namespace() != b.Namespace() {
+				return a.Namespace() < b.Namespace()
+			}
+			return a.Name() < b.Name()
+		})
+	}
+	return s
+}
+
+func typeKey(group, version, plural string) string {
+	return group + "/" + version + "/" + plural
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "the snapshot API server is read-only")
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case path == "version":
+		s.serveVersion(w)
+	case path == "api":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"kind":     "APIVersions",
+			"versions": []string{"v1"},
+			"serverAddressByClientCIDRs": []map[string]string{
+				{"clientCIDR": "0.0.0.0/0", "serverAddress": r.Host},
+			},
+		})
+	case path == "apis":
+		s.serveGroups(w)
+	case path == "healthz" || path == "livez" || path == "readyz":
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "ok")
+	case path == "api/v1":
+		s.serveResourceList(w, "", "v1")
+	case strings.HasPrefix(path, "api/v1/"):
+		s.serveResources(w, r, "", "v1", strings.Split(strings.TrimPrefix(path, "api/v1/"), "/"))
+	case strings.HasPrefix(path, "apis/"):
+		parts := strings.Split(strings.TrimPrefix(path, "apis/"), "/")
+		switch len(parts) {
+		case 1:
+			s.serveGroup(w, parts[0])
+		case 2:
+			s.serveResourceList(w, parts[0], parts[1])
+		default:
+			s.serveResources(w, r, parts[0], parts[1], parts[2:])
+		}
+	default:
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("the server could not find the requested resource (%s)", r.URL.Path))
+	}
+}
+
+func (s *Server) serveVersion(w http.ResponseWriter) {
+	gitVersion := export.DetectKubernetesVersion(s.snapshot.Resources)
+	major, minor := "", ""
+	if parts := strings.SplitN(strings.TrimPrefix(gitVersion, "v"), ".", 3); len(parts) >= 2 {
+		major, minor = parts[0], parts[1]
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"major":      major,
+		"minor":      minor,
+		"gitVersion": gitVersion,
+		"platform":   "meshsync-snapshot",
+	})
+}
+
+// serveResources handles the paths below a group version:
+//
+//	{resource}                          list across namespaces
+//	{resource}/{name}                   get a cluster-scoped resource
+//	namespaces/{ns}/{resource}          list in a namespace
+//	namespaces/{ns}/{resource}/{name}   get a namespaced resource
+func (s *Server) serveResources(w http.ResponseWriter, r *http.Request, group, version string, parts []string) {
+	var namespace, plural, name string
+	switch {
+	case len(parts) == 1:
+		plural = parts[0]
+	case len(parts) == 2:
+		plural, name = parts[0], parts[1]
+	case len(parts) == 3 && parts[0] == "namespaces":
+		namespace, plural = parts[1], parts[2]
+	case len(parts) == 4 && parts[0] == "namespaces":
+		namespace, plural, name = parts[1], parts[2], parts[3]
+	default:
+		writeStatus(w, http.StatusNotFound, "NotFound", "subresources are not served from a snapshot")
+		return
+	}
+
+	t, ok := s.types[typeKey(group, version, plural)]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("the server could not find the requested resource (get %s)", plural))
+		return
+	}
+
+	if name != "" {
+		for _, resource := range t.Resources {
+			if resource.Name() == name && resource.Namespace() == namespace {
+				s.writeObjects(w, r, t, []*models.KubernetesResource{resource}, true)
+				return
+			}
+		}
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s %q not found", plural, name))
+		return
+	}
+
+	labels, err := selectors.ParseLabelSelector(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	fields, err := selectors.ParseFieldSelector(r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("watch") == "true" || r.URL.Query().Get("watch") == "1" {
+		serveWatch(w, r)
+		return
+	}
+
+	var matched []*models.KubernetesResource
+	for _, resource := range t.Resources {
+		if namespace != "" && resource.Namespace() != namespace {
+			continue
+		}
+		if !labels.Matches(resource.KubernetesResourceMeta.LabelMap()) {
+			continue
+		}
+		if !fields.Matches(resource.Object()) {
+			continue
+		}
+		matched = append(matched, resource)
+	}
+	s.writeObjects(w, r, t, matched, false)
+}
+
+func (s *Server) writeObjects(w http.ResponseWriter, r *http.Request, t *resourceType, resources []*models.KubernetesResource, single bool) {
+	if wantsTable(r) {
+		writeJSON(w, http.StatusOK, s.table(resources))
+		return
+	}
+	if single {
+		writeJSON(w, http.StatusOK, resources[0].Object())
+		return
+	}
+
+	items := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		items = append(items, resource.Object())
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":       t.Kind + "List",
+		"apiVersion": joinGroupVersion(t.Group, t.Version),
+		"metadata":   map[string]string{"resourceVersion": snapshotResourceVersion},
+		"items":      items,
+	})
+}
+
+// wantsTable reports whether the client asked for server-side printing, as
+// kubectl get does, via "as=Table" in the Accept header.
+func wantsTable(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if strings.Contains(accept, "as=Table") {
+			return true
+		}
+	}
+	return false
+}
+
+// table builds a meta.k8s.io/v1 Table using the query package's columns,
+// with the -o wide columns at priority 1 so kubectl hides them by default.
+func (s *Server) table(resources []*models.KubernetesResource) map[string]interface{} {
+	columns := []map[string]interface{}{}
+	rows := []map[string]interface{}{}
+	if len(resources) > 0 {
+		headers, narrow, cells := s.session.Table(resources)
+		for i, header := range headers {
+			priority := 0
+			if i >= narrow {
+				priority = 1
+			}
+			column := map[string]interface{}{
+				"name":        header,
+				"type":        "string",
+				"format":      "",
+				"description": "",
+				"priority":    priority,
+			}
+			if i == 0 {
+				column["format"] = "name"
+			}
+			columns = append(columns, column)
+		}
+		for i, resource := range resources {
+			row := make([]interface{}, len(cells[i]))
+			for j, cell := range cells[i] {
+				row[j] = cell
+			}
+			rows = append(rows, map[string]interface{}{
+				"cells":  row,
+				"object": resource.Object(),
+			})
+		}
+	}
+	return map[string]interface{}{
+		"kind":              "Table",
+		"apiVersion":        "meta.k8s.io/v1",
+		"metadata":          map[string]string{"resourceVersion": snapshotResourceVersion},
+		"columnDefinitions": columns,
+		"rows":              rows,
+	}
+}
+
+// serveWatch holds the connection open without sending events until the
+// client goes away: nothing ever changes in a snapshot.
+func serveWatch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	<-r.Context().Done()
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]interface{}{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
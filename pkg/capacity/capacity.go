@@ -0,0 +1,326 @@
+package capacity
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+// roundingSlack absorbs float error so that "0.1" becomes 100m, not 101m.
+const roundingSlack = 1e-6
+
+type Options struct {
+	Namespace string
+}
+
+// Usage holds summed requests and limits; CPU is in millicores and memory
+// in bytes.
+type Usage struct {
+	CPURequests    int64 `json:"cpu_requests_millicores"`
+	CPULimits      int64 `json:"cpu_limits_millicores"`
+	MemoryRequests int64 `json:"memory_requests_bytes"`
+	MemoryLimits   int64 `json:"memory_limits_bytes"`
+}
+
+func (u *Usage) add(other Usage) {
+	u.CPURequests += other.CPURequests
+	u.CPULimits += other.CPULimits
+	u.MemoryRequests += other.MemoryRequests
+	u.MemoryLimits += other.MemoryLimits
+}
+
+type Report struct {
+	ClusterID string `json:"cluster_id"`
+	Timestamp string `json:"timestamp,omitempty"`
+	// QuotasCaptured is false when the snapshot holds no ResourceQuotas at
+	// all, as with older snapshots or a capture filtered by type, so an
+	// empty Quotas list says nothing about the namespace.
+	QuotasCaptured bool                `json:"quotas_captured"`
+	Nodes          []NodeCapacity      `json:"nodes"`
+	Namespaces     []NamespaceCapacity `json:"namespaces"`
+	Workloads      []WorkloadCapacity  `json:"workloads"`
+}
+
+type NodeCapacity struct {
+	Name              string   `json:"name"`
+	Pods              int      `json:"pods"`
+	AllocatableCPU    int64    `json:"allocatable_cpu_millicores"`
+	AllocatableMemory int64    `json:"allocatable_memory_bytes"`
+	Usage             Usage    `json:"usage"`
+	Overcommitted     []string `json:"overcommitted,omitempty"`
+}
+
+type NamespaceCapacity struct {
+	Name   string   `json:"name"`
+	Pods   int      `json:"pods"`
+	Usage  Usage    `json:"usage"`
+	Quotas []string `json:"quotas"`
+}
+
+type WorkloadCapacity struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Pods      int    `json:"pods"`
+	Usage     Usage  `json:"usage"`
+}
+
+// Build sums the effective requests and limits of every Pod that is not
+// Succeeded or Failed. Node totals always cover every Pod on the node so
+// that overcommit is judged correctly even when scoped to one namespace.
+func Build(snap *models.Snapshot, opts Options) *Report {
+	report := &Report{
+		ClusterID:  snap.ClusterID,
+		Timestamp:  snap.Timestamp,
+		Nodes:      []NodeCapacity{},
+		Namespaces: []NamespaceCapacity{},
+		Workloads:  []WorkloadCapacity{},
+	}
+
+	byUID := make(map[string]*models.KubernetesResource)
+	for _, resource := range snap.Resources {
+		if resource != nil && resource.KubernetesResourceMeta != nil {
+			byUID[resource.UID()] = resource
+		}
+	}
+
+	nodes := make(map[string]*NodeCapacity)
+	namespaces := make(map[string]*NamespaceCapacity)
+	workloads := make(map[string]*WorkloadCapacity)
+
+	namespace := func(name string) *NamespaceCapacity {
+		if ns, ok := namespaces[name]; ok {
+			return ns
+		}
+		ns := &NamespaceCapacity{Name: name, Quotas: []string{}}
+		namespaces[name] = ns
+		return ns
+	}
+
+	for _, resource := range snap.Resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		inScope := opts.Namespace == "" || resource.Namespace() == opts.Namespace
+		switch resource.Kind {
+		case "Node":
+			status, _ := resource.Status.Decode()
+			allocatable, _ := status["allocatable"].(map[string]interface{})
+			node := nodeEntry(nodes, resource.Name())
+			node.AllocatableCPU = milli(allocatable["cpu"])
+			node.AllocatableMemory = whole(allocatable["memory"])
+		case "Namespace":
+			if opts.Namespace == "" || resource.Name() == opts.Namespace {
+				namespace(resource.Name())
+			}
+		case "ResourceQuota":
+			report.QuotasCaptured = true
+			if inScope {
+				ns := namespace(resource.Namespace())
+				ns.Quotas = append(ns.Quotas, resource.Name())
+			}
+		case "Pod":
+			spec, err := resource.Spec.Decode()
+			if err != nil {
+				continue
+			}
+			status, _ := resource.Status.Decode()
+			if phase, _ := status["phase"].(string); phase == "Succeeded" || phase == "Failed" {
+				continue
+			}
+			usage := PodUsage(spec)
+
+			if nodeName, _ := spec["nodeName"].(string); nodeName != "" {
+				node := nodeEntry(nodes, nodeName)
+				node.Pods++
+				node.Usage.add(usage)
+			}
+			if !inScope {
+				continue
+			}
+
+			ns := namespace(resource.Namespace())
+			ns.Pods++
+			ns.Usage.add(usage)
+
+			kind, name := rootOwner(resource, byUID)
+			key := kind + "/" + resource.Namespace() + "/" + name
+			workload, ok := workloads[key]
+			if !ok {
+				workload = &WorkloadCapacity{Kind: kind, Namespace: resource.Namespace(), Name: name}
+				workloads[key] = workload
+			}
+			workload.Pods++
+			workload.Usage.add(usage)
+		}
+	}
+
+	for _, node := range nodes {
+		node.Overcommitted = overcommitted(node)
+		report.Nodes = append(report.Nodes, *node)
+	}
+	for _, ns := range namespaces {
+		sort.Strings(ns.Quotas)
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	for _, workload := range workloads {
+		report.Workloads = append(report.Workloads, *workload)
+	}
+
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+	sort.Slice(report.Namespaces, func(i, j int) bool { return report.Namespaces[i].Name < report.Namespaces[j].Name })
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i], report.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+func nodeEntry(nodes map[string]*NodeCapacity, name string) *NodeCapacity {
+	if node, ok := nodes[name]; ok {
+		return node
+	}
+	node := &NodeCapacity{Name: name}
+	nodes[name] = node
+	return node
+}
+
+func overcommitted(node *NodeCapacity) []string {
+	var flags []string
+	if node.AllocatableCPU > 0 {
+		if node.Usage.CPURequests > node.AllocatableCPU {
+			flags = append(flags, "cpu-requests")
+		}
+		if node.Usage.CPULimits > node.AllocatableCPU {
+			flags = append(flags, "cpu-limits")
+		}
+	}
+	if node.AllocatableMemory > 0 {
+		if node.Usage.MemoryRequests > node.AllocatableMemory {
+			flags = append(flags, "memory-requests")
+		}
+		if node.Usage.MemoryLimits > node.AllocatableMemory {
+			flags = append(flags, "memory-limits")
+		}
+	}
+	return flags
+}
+
+// PodUsage computes a Pod's effective requests and limits the way the
+// scheduler does: the larger of the summed app containers (plus sidecar
+// init containers) and the largest regular init container, plus overhead.
+func PodUsage(podSpec map[string]interface{}) Usage {
+	var sum, initMax Usage
+	for _, container := range utils.Containers(podSpec, "containers") {
+		sum.add(containerUsage(container))
+	}
+	for _, container := range utils.Containers(podSpec, "initContainers") {
+		usage := containerUsage(container)
+		if policy, _ := container["restartPolicy"].(string); policy == "Always" {
+			sum.add(usage)
+			continue
+		}
+		initMax.CPURequests = max(initMax.CPURequests, usage.CPURequests)
+		initMax.CPULimits = max(initMax.CPULimits, usage.CPULimits)
+		initMax.MemoryRequests = max(initMax.MemoryRequests, usage.MemoryRequests)
+		initMax.MemoryLimits = max(initMax.MemoryLimits, usage.MemoryLimits)
+	}
+
+	usage := Usage{
+		CPURequests:    max(sum.CPURequests, initMax.CPURequests),
+		CPULimits:      max(sum.CPULimits, initMax.CPULimits),
+		MemoryRequests: max(sum.MemoryRequests, initMax.MemoryRequests),
+		MemoryLimits:   max(sum.MemoryLimits, initMax.MemoryLimits),
+	}
+	if overhead, ok := podSpec["overhead"].(map[string]interface{}); ok {
+		usage.add(Usage{
+			CPURequests:    milli(overhead["cpu"]),
+			CPULimits:      milli(overhead["cpu"]),
+			MemoryRequests: whole(overhead["memory"]),
+			MemoryLimits:   whole(overhead["memory"]),
+		})
+	}
+	return usage
+}
+
+// containerUsage reads requests and limits; as in the API server, a limit
+// without a request implies an equal request.
+func containerUsage(container map[string]interface{}) Usage {
+	resources, _ := container["resources"].(map[string]interface{})
+	requests, _ := resources["requests"].(map[string]interface{})
+	limits, _ := resources["limits"].(map[string]interface{})
+
+	usage := Usage{
+		CPURequests:    milli(requests["cpu"]),
+		CPULimits:      milli(limits["cpu"]),
+		MemoryRequests: whole(requests["memory"]),
+		MemoryLimits:   whole(limits["memory"]),
+	}
+	if _, ok := requests["cpu"]; !ok {
+		usage.CPURequests = usage.CPULimits
+	}
+	if _, ok := requests["memory"]; !ok {
+		usage.MemoryRequests = usage.MemoryLimits
+	}
+	return usage
+}
+
+// rootOwner follows controller owner references up to the top-level
+// workload. Owners missing from the snapshot are reported by reference
+// (a ReplicaSet named after its pod-template-hash is taken to belong to a
+// Deployment), and static Pods owned by their Node are their own workload.
+func rootOwner(pod *models.KubernetesResource, byUID map[string]*models.KubernetesResource) (string, string) {
+	current := pod
+	kind, name := pod.Kind, pod.Name()
+	for depth := 0; depth < 10; depth++ {
+		refs, err := current.KubernetesResourceMeta.DecodeOwnerReferences()
+		if err != nil {
+			break
+		}
+		var controller *models.OwnerReference
+		for i := range refs {
+			if refs[i].IsController() && refs[i].Kind != "Node" {
+				controller = &refs[i]
+				break
+			}
+		}
+		if controller == nil {
+			break
+		}
+		kind, name = controller.Kind, controller.Name
+		owner, ok := byUID[controller.UID]
+		if !ok {
+			if hash := current.KubernetesResourceMeta.LabelMap()["pod-template-hash"]; kind == "ReplicaSet" && hash != "" && strings.HasSuffix(name, "-"+hash) {
+				kind, name = "Deployment", strings.TrimSuffix(name, "-"+hash)
+			}
+			break
+		}
+		current = owner
+	}
+	return kind, name
+}
+
+func milli(value interface{}) int64 {
+	quantity, err := utils.ParseQuantity(value)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Ceil(quantity*1000 - roundingSlack))
+}
+
+func whole(value interface{}) int64 {
+	quantity, err := utils.ParseQuantity(value)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Ceil(quantity - roundingSlack))
+}
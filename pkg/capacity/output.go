@@ -0,0 +1,106 @@
+package capacity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatTable:
+		return r.writeTable(w)
+	case FormatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal capacity report: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	default:
+		return fmt.Errorf("unsupported capacity format %q (use table or json)", format)
+	}
+}
+
+func (r *Report) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "NODE\tPODS\tCPU REQUESTS\tCPU LIMITS\tCPU ALLOCATABLE\tMEMORY REQUESTS\tMEMORY LIMITS\tMEMORY ALLOCATABLE\tOVERCOMMITTED")
+	for _, node := range r.Nodes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", node.Name, node.Pods,
+			withPercent(FormatCPU(node.Usage.CPURequests), node.Usage.CPURequests, node.AllocatableCPU),
+			withPercent(FormatCPU(node.Usage.CPULimits), node.Usage.CPULimits, node.AllocatableCPU),
+			FormatCPU(node.AllocatableCPU),
+			withPercent(FormatMemory(node.Usage.MemoryRequests), node.Usage.MemoryRequests, node.AllocatableMemory),
+			withPercent(FormatMemory(node.Usage.MemoryLimits), node.Usage.MemoryLimits, node.AllocatableMemory),
+			FormatMemory(node.AllocatableMemory),
+			listOr(node.Overcommitted, "-"))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "NAMESPACE\tPODS\tCPU REQUESTS\tCPU LIMITS\tMEMORY REQUESTS\tMEMORY LIMITS\tQUOTA")
+	noQuota := "none"
+	if !r.QuotasCaptured {
+		noQuota = "not captured"
+	}
+	for _, ns := range r.Namespaces {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", ns.Name, ns.Pods,
+			FormatCPU(ns.Usage.CPURequests), FormatCPU(ns.Usage.CPULimits),
+			FormatMemory(ns.Usage.MemoryRequests), FormatMemory(ns.Usage.MemoryLimits),
+			listOr(ns.Quotas, noQuota))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "WORKLOAD\tNAMESPACE\tPODS\tCPU REQUESTS\tCPU LIMITS\tMEMORY REQUESTS\tMEMORY LIMITS")
+	for _, workload := range r.Workloads {
+		fmt.Fprintf(tw, "%s/%s\t%s\t%d\t%s\t%s\t%s\t%s\n", workload.Kind, workload.Name, workload.Namespace, workload.Pods,
+			FormatCPU(workload.Usage.CPURequests), FormatCPU(workload.Usage.CPULimits),
+			FormatMemory(workload.Usage.MemoryRequests), FormatMemory(workload.Usage.MemoryLimits))
+	}
+
+	return tw.Flush()
+}
+
+// FormatCPU renders millicores as whole cores when exact, e.g. "2" or "250m".
+func FormatCPU(millicores int64) string {
+	if millicores%1000 == 0 {
+		return strconv.FormatInt(millicores/1000, 10)
+	}
+	return strconv.FormatInt(millicores, 10) + "m"
+}
+
+// FormatMemory renders bytes with the largest binary suffix that keeps one
+// decimal place of precision, e.g. "512Mi" or "1.5Gi".
+func FormatMemory(bytes int64) string {
+	suffixes := []string{"Ei", "Pi", "Ti", "Gi", "Mi", "Ki"}
+	for i, suffix := range suffixes {
+		unit := int64(1) << (10 * (len(suffixes) - i))
+		if bytes >= unit {
+			value := strconv.FormatFloat(float64(bytes)/float64(unit), 'f', 1, 64)
+			return strings.TrimSuffix(value, ".0") + suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+func withPercent(value string, used, total int64) string {
+	if total <= 0 {
+		return value
+	}
+	return fmt.Sprintf("%s (%d%%)", value, used*100/total)
+}
+
+func listOr(values []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return strings.Join(values, ",")
+}
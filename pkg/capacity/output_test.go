@@ -0,0 +1,66 @@
+package capacity
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestQuotaColumn(t *testing.T) {
+	resource := func(kind, namespace, name string) *models.KubernetesResource {
+		return &models.KubernetesResource{
+			APIVersion:             "v1",
+			Kind:                   kind,
+			KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: name, Namespace: namespace},
+		}
+	}
+	tests := []struct {
+		name      string
+		resources []*models.KubernetesResource
+		want      map[string]string
+	}{
+		{
+			name:      "quotas not captured",
+			resources: []*models.KubernetesResource{resource("Namespace", "", "shop")},
+			want:      map[string]string{"shop": "not captured"},
+		},
+		{
+			name: "quotas captured",
+			resources: []*models.KubernetesResource{
+				resource("Namespace", "", "shop"),
+				resource("Namespace", "", "billing"),
+				resource("ResourceQuota", "billing", "compute"),
+			},
+			want: map[string]string{"shop": "none", "billing": "compute"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Build(&models.Snapshot{Resources: tt.resources}, Options{})
+			var out bytes.Buffer
+			if err := report.Write(&out, FormatTable); err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			inNamespaces := false
+			for _, line := range strings.Split(out.String(), "\n") {
+				switch {
+				case strings.HasPrefix(line, "NAMESPACE"):
+					inNamespaces = true
+				case line == "":
+					inNamespaces = false
+				case inNamespaces:
+					fields := strings.Fields(line)
+					got[fields[0]] = strings.Join(fields[6:], " ")
+				}
+			}
+			for namespace, want := range tt.want {
+				if got[namespace] != want {
+					t.Errorf("QUOTA for %s = %q, want %q\n%s", namespace, got[namespace], want, out.String())
+				}
+			}
+		})
+	}
+}
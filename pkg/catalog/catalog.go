@@ -0,0 +1,318 @@
+package catalog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const fileName = "catalog.db"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS snapshots (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	cluster_id     TEXT NOT NULL,
+	context        TEXT NOT NULL DEFAULT '',
+	timestamp      TEXT NOT NULL,
+	path           TEXT NOT NULL,
+	filters        TEXT NOT NULL DEFAULT '{}',
+	resource_count INTEGER NOT NULL,
+	size           INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS snapshots_cluster ON snapshots (cluster_id, timestamp);
+
+CREATE TABLE IF NOT EXISTS snapshot_kinds (
+	snapshot_id INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+	kind        TEXT NOT NULL,
+	count       INTEGER NOT NULL,
+	PRIMARY KEY (snapshot_id, kind)
+);
+
+CREATE TABLE IF NOT EXISTS resources (
+	snapshot_id INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+	kind        TEXT NOT NULL,
+	api_version TEXT NOT NULL,
+	namespace   TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	uid         TEXT NOT NULL,
+	labels      TEXT NOT NULL,
+	object      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS resources_lookup ON resources (kind, namespace, name);
+CREATE INDEX IF NOT EXISTS resources_snapshot ON resources (snapshot_id);
+`
+
+// Catalog indexes captured snapshots in a SQLite database. Each snapshot is
+// recorded with its per-kind counts; resources can also be normalized into
+// the resources table, with labels and the object as JSON, so that
+// json_extract queries work across snapshots.
+type Catalog struct {
+	db   *sql.DB
+	path string
+}
+
+type Entry struct {
+	ID        int64                  `json:"id"`
+	ClusterID string                 `json:"cluster_id"`
+	Context   string                 `json:"context,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Path      string                 `json:"path"`
+	Filters   map[string]interface{} `json:"filters,omitempty"`
+	Resources int                    `json:"resources"`
+	Size      int64                  `json:"size"`
+	Kinds     map[string]int         `json:"kinds,omitempty"`
+}
+
+// DefaultPath is ~/.kube/meshsync-snapshots/catalog.db.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".kube", "meshsync-snapshots", fileName), nil
+}
+
+func Open(path string) (*Catalog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create catalog directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open catalog: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize catalog %s: %w", path, err)
+	}
+	return &Catalog{db: db, path: path}, nil
+}
+
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+func (c *Catalog) Close() error {
+	return c.db.Close()
+}
+
+func KindCounts(resources []*models.KubernetesResource) map[string]int {
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		if resource != nil && resource.Kind != "" {
+			counts[resource.Kind]++
+		}
+	}
+	return counts
+}
+
+// Record adds a snapshot to the catalog and returns its ID. When normalize
+// is set, every resource is also written to the resources table.
+func (c *Catalog) Record(entry *Entry, resources []*models.KubernetesResource, normalize bool) (int64, error) {
+	filters, err := json.Marshal(entry.Filters)
+	if err != nil {
+		return 0, err
+	}
+	if entry.Kinds == nil {
+		entry.Kinds = KindCounts(resources)
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO snapshots (cluster_id, context, timestamp, path, filters, resource_count, size) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.ClusterID, entry.Context, entry.Timestamp.UTC().Format(time.RFC3339), entry.Path, string(filters), entry.Resources, entry.Size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record snapshot: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for kind, count := range entry.Kinds {
+		if _, err := tx.Exec(`INSERT INTO snapshot_kinds (snapshot_id, kind, count) VALUES (?, ?, ?)`, id, kind, count); err != nil {
+			return 0, fmt.Errorf("failed to record kind counts: %w", err)
+		}
+	}
+
+	if normalize {
+		stmt, err := tx.Prepare(`INSERT INTO resources (snapshot_id, kind, api_version, namespace, name, uid, labels, object) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
+		for _, resource := range resources {
+			if resource == nil || resource.KubernetesResourceMeta == nil {
+				continue
+			}
+			labels, err := json.Marshal(resource.KubernetesResourceMeta.LabelMap())
+			if err != nil {
+				return 0, err
+			}
+			object, err := json.Marshal(resource.Object())
+			if err != nil {
+				return 0, err
+			}
+			if _, err := stmt.Exec(id, resource.Kind, resource.APIVersion, resource.Namespace(), resource.Name(), resource.UID(), string(labels), string(object)); err != nil {
+				return 0, fmt.Errorf("failed to record resources: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	entry.ID = id
+	return id, nil
+}
+
+// List returns snapshots newest first, optionally for one cluster. A limit
+// of zero lists everything.
+func (c *Catalog) List(clusterID string, limit int) ([]*Entry, error) {
+	query := `SELECT id, cluster_id, context, timestamp, path, filters, resource_count, size FROM snapshots`
+	var args []interface{}
+	if clusterID != "" {
+		query += ` WHERE cluster_id = ?`
+		args = append(args, clusterID)
+	}
+	query += ` ORDER BY timestamp DESC, id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Get returns one snapshot with its per-kind counts.
+func (c *Catalog) Get(id int64) (*Entry, error) {
+	row := c.db.QueryRow(`SELECT id, cluster_id, context, timestamp, path, filters, resource_count, size FROM snapshots WHERE id = ?`, id)
+	entry, err := scanEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no snapshot with ID %d in the catalog", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.Query(`SELECT kind, count FROM snapshot_kinds WHERE snapshot_id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	defer rows.Close()
+	entry.Kinds = make(map[string]int)
+	for rows.Next() {
+		var kind string
+		var count int
+		if err := rows.Scan(&kind, &count); err != nil {
+			return nil, err
+		}
+		entry.Kinds[kind] = count
+	}
+	return entry, rows.Err()
+}
+
+// Expired returns the snapshots beyond the newest keep of each cluster.
+func (c *Catalog) Expired(keep int, clusterID string) ([]*Entry, error) {
+	entries, err := c.List(clusterID, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]int)
+	var expired []*Entry
+	for _, entry := range entries {
+		seen[entry.ClusterID]++
+		if seen[entry.ClusterID] > keep {
+			expired = append(expired, entry)
+		}
+	}
+	sort.SliceStable(expired, func(i, j int) bool { return expired[i].ID < expired[j].ID })
+	return expired, nil
+}
+
+// PathInUse reports whether any entry other than those in excluding records
+// path. Captures without --auto-name all write the same file, so several
+// entries can share one.
+func (c *Catalog) PathInUse(path string, excluding ...int64) (bool, error) {
+	query := `SELECT COUNT(*) FROM snapshots WHERE path = ?`
+	args := []interface{}{path}
+	if len(excluding) > 0 {
+		query += ` AND id NOT IN (?` + strings.Repeat(`, ?`, len(excluding)-1) + `)`
+		for _, id := range excluding {
+			args = append(args, id)
+		}
+	}
+	var count int
+	if err := c.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Delete removes snapshots, and any normalized resources, from the catalog.
+// Snapshot files are left alone.
+func (c *Catalog) Delete(ids ...int64) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM snapshots WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete snapshot %d: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEntry(row scanner) (*Entry, error) {
+	var entry Entry
+	var timestamp, filters string
+	if err := row.Scan(&entry.ID, &entry.ClusterID, &entry.Context, &timestamp, &entry.Path, &filters, &entry.Resources, &entry.Size); err != nil {
+		return nil, err
+	}
+	entry.Timestamp, _ = time.Parse(time.RFC3339, timestamp)
+	if err := json.Unmarshal([]byte(filters), &entry.Filters); err != nil {
+		entry.Filters = nil
+	}
+	return &entry, nil
+}
+
+// DeletePath removes the entries recorded for a snapshot file.
+func (c *Catalog) DeletePath(path string) error {
+	if _, err := c.db.Exec(`DELETE FROM snapshots WHERE path = ?`, path); err != nil {
+		return fmt.Errorf("failed to delete %s from catalog: %w", path, err)
+	}
+	return nil
+}
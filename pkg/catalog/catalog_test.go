@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := Open(filepath.Join(t.TempDir(), fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func record(t *testing.T, c *Catalog, clusterID, path string, at time.Time) int64 {
+	t.Helper()
+	id, err := c.Record(&Entry{ClusterID: clusterID, Timestamp: at, Path: path}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestExpired(t *testing.T) {
+	c := openTestCatalog(t)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a1 := record(t, c, "a", "a1.json", base)
+	a2 := record(t, c, "a", "a2.json", base.Add(time.Hour))
+	a3 := record(t, c, "a", "a3.json", base.Add(2*time.Hour))
+	b1 := record(t, c, "b", "b1.json", base)
+
+	tests := []struct {
+		name    string
+		keep    int
+		cluster string
+		want    []int64
+	}{
+		{name: "keep one per cluster", keep: 1, want: []int64{a1, a2}},
+		{name: "keep two", keep: 2, want: []int64{a1}},
+		{name: "keep none", keep: 0, want: []int64{a1, a2, a3, b1}},
+		{name: "one cluster", keep: 0, cluster: "b", want: []int64{b1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, err := c.Expired(tt.keep, tt.cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, entry := range expired {
+				got = append(got, entry.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expired = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expired = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathInUseWithSharedPaths(t *testing.T) {
+	c := openTestCatalog(t)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Captures without --auto-name all record the default path.
+	old := record(t, c, "a", "meshsync-snapshot.json", base)
+	record(t, c, "a", "meshsync-snapshot.json", base.Add(time.Hour))
+	unique := record(t, c, "a", "unique.json", base.Add(-time.Hour))
+
+	expired, err := c.Expired(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, entry := range expired {
+		ids = append(ids, entry.ID)
+	}
+	if len(ids) != 2 || ids[0] != old || ids[1] != unique {
+		t.Fatalf("Expired = %v, want [%d %d]", ids, old, unique)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "meshsync-snapshot.json", want: true},
+		{path: "unique.json", want: false},
+		{path: "never-recorded.json", want: false},
+	}
+	for _, tt := range tests {
+		inUse, err := c.PathInUse(tt.path, ids...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inUse != tt.want {
+			t.Errorf("PathInUse(%s) = %v, want %v", tt.path, inUse, tt.want)
+		}
+	}
+
+	if inUse, err := c.PathInUse("unique.json"); err != nil || !inUse {
+		t.Errorf("PathInUse without exclusions = %v, %v, want true", inUse, err)
+	}
+}
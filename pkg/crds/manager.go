@@ -3,6 +3,7 @@ package crds
 import (
 	"fmt"
 	"io/ioutil"
+	"log/slog"
 	"os"
 	"os/exec"
 	"time"
@@ -10,9 +11,12 @@ import (
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
 )
 
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
 type Manager struct {
 	crdFilePath string
 	applied     bool
+	inCluster   bool
 	options     *models.Options
 }
 
@@ -20,14 +24,28 @@ func NewManager(crdFilePath string, options *models.Options) *Manager {
 	return &Manager{
 		crdFilePath: crdFilePath,
 		applied:     false,
+		inCluster:   InCluster(),
 		options:     options,
 	}
 }
 
-func (m *Manager) Apply() error {
-	if !m.options.QuietMode {
-		fmt.Println("Applying MeshSync CRDs...")
+// InCluster reports whether the plugin is running in a pod, where kubectl
+// uses the service account and workstation setup such as /etc/hosts
+// entries is neither possible nor needed.
+func InCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return false
 	}
+	_, err := os.Stat(serviceAccountTokenPath)
+	return err == nil
+}
+
+func (m *Manager) InCluster() bool {
+	return m.inCluster
+}
+
+func (m *Manager) Apply() error {
+	slog.Info("Applying MeshSync CRDs")
 
 	if m.options.PreviewMode {
 		return nil
@@ -51,6 +69,9 @@ func (m *Manager) Apply() error {
 	if err != nil {
 		return fmt.Errorf("failed to apply CRDs: %w\nOutput: %s", err, output)
 	}
+	// From here on a failure leaves objects behind, and Remove ignores
+	// anything that was never created.
+	m.applied = true
 
 	namespaceYAML := `
 apiVersion: v1
@@ -105,9 +126,7 @@ spec:
 		return fmt.Errorf("failed to apply Broker instance: %w\nOutput: %s", err, output)
 	}
 
-	if !m.options.QuietMode {
-		fmt.Println("Waiting for broker to initialize...")
-	}
+	slog.Info("Waiting for broker to initialize")
 
 	meshSyncYAML := `
 apiVersion: meshery.io/v1alpha1
@@ -123,7 +142,7 @@ spec:
   size: 1
   watch-list:
     data:
-      whitelist: '[{"Resource":"namespaces.v1.","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"configmaps.v1.","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"nodes.v1.","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"pods.v1.","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"services.v1.","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"deployments.v1.apps","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"statefulsets.v1.apps","Events":["ADDED","MODIFIED","DELETED"]},{"Resource":"daemonsets.v1.apps","Events":["ADDED","MODIFIED","DELETED"]}]'
+      whitelist: '` + whitelistJSON() + `'
 `
 	tmpMeshSyncFile, err := ioutil.TempFile("", "meshsync-instance-*.yaml")
 	if err != nil {
@@ -145,9 +164,7 @@ spec:
 	}
 
 	m.applied = true
-	if !m.options.QuietMode {
-		fmt.Println("MeshSync CRDs and instance applied successfully")
-	}
+	slog.Info("MeshSync CRDs and instance applied")
 	return nil
 }
 
@@ -156,16 +173,12 @@ func (m *Manager) Remove() error {
 		return nil
 	}
 
-	if !m.options.QuietMode {
-		fmt.Println("Removing MeshSync instance and CRDs...")
-	}
+	slog.Info("Removing MeshSync instance and CRDs")
 
 	cmd := exec.Command("kubectl", "delete", "meshsync", "meshery-meshsync", "-n", "meshery", "--ignore-not-found=true")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		if !m.options.QuietMode {
-			fmt.Printf("Warning: failed to remove MeshSync instance: %v\nOutput: %s\n", err, output)
-		}
+		slog.Warn("Failed to remove MeshSync instance", "error", err, "output", string(output))
 	}
 
 	time.Sleep(500 * time.Millisecond)
@@ -173,33 +186,29 @@ func (m *Manager) Remove() error {
 	cmd = exec.Command("kubectl", "delete", "broker", "meshery-broker", "-n", "meshery", "--ignore-not-found=true")
 	output, err = cmd.CombinedOutput()
 	if err != nil {
-		if !m.options.QuietMode {
-			fmt.Printf("Warning: failed to remove Broker instance: %v\nOutput: %s\n", err, output)
-		}
+		slog.Warn("Failed to remove Broker instance", "error", err, "output", string(output))
 	}
 
 	time.Sleep(1 * time.Second)
 
 	cmd = exec.Command("kubectl", "delete", "namespace", "meshery", "--ignore-not-found=true")
 	_, err = cmd.CombinedOutput()
-	if err != nil && !m.options.QuietMode {
-		fmt.Printf("Warning: failed to remove namespace: %v\n", err)
+	if err != nil {
+		slog.Warn("Failed to remove namespace", "namespace", "meshery", "error", err)
 	}
 
 	if _, err := os.Stat(m.crdFilePath); err == nil {
 		cmd = exec.Command("kubectl", "delete", "-f", m.crdFilePath, "--ignore-not-found=true")
 		output, err = cmd.CombinedOutput()
-		if err != nil && !m.options.QuietMode {
-			fmt.Printf("Warning: failed to remove CRDs: %v\nOutput: %s\n", err, output)
+		if err != nil {
+			slog.Warn("Failed to remove CRDs", "error", err, "output", string(output))
 		}
 	}
 
 	os.Remove(m.crdFilePath)
 	m.applied = false
 
-	if !m.options.QuietMode {
-		fmt.Println("MeshSync instance and CRDs removed")
-	}
+	slog.Info("MeshSync instance and CRDs removed")
 	return nil
 }
 
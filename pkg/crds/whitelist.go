@@ -0,0 +1,50 @@
+package crds
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// WatchedResource is a resource MeshSync is told to watch.
+type WatchedResource struct {
+	Group    string
+	Version  string
+	Resource string
+}
+
+// WatchedResources is the MeshSync whitelist. Anything that needs to read
+// what a capture reads, such as the in-cluster job's ClusterRole, should
+// derive from it.
+var WatchedResources = []WatchedResource{
+	{Version: "v1", Resource: "namespaces"},
+	{Version: "v1", Resource: "configmaps"},
+	{Version: "v1", Resource: "nodes"},
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "services"},
+	{Version: "v1", Resource: "resourcequotas"},
+	{Version: "v1", Resource: "serviceaccounts"},
+	{Version: "v1", Resource: "persistentvolumeclaims"},
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "replicasets"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+}
+
+// whitelistJSON renders WatchedResources in the form the MeshSync CR's
+// watch-list expects, with resources named plural.version.group.
+func whitelistJSON() string {
+	type entry struct {
+		Resource string
+		Events   []string
+	}
+	entries := make([]entry, 0, len(WatchedResources))
+	for _, r := range WatchedResources {
+		entries = append(entries, entry{
+			Resource: strings.Join([]string{r.Resource, r.Version, r.Group}, "."),
+			Events:   []string{"ADDED", "MODIFIED", "DELETED"},
+		})
+	}
+	data, _ := json.Marshal(entries)
+	return string(data)
+}
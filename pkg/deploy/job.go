@@ -0,0 +1,291 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/crds"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/storage"
+)
+
+const (
+	DefaultName      = "meshsync-snapshot"
+	DefaultNamespace = "meshsync-snapshot"
+	DefaultSchedule  = "0 * * * *"
+	DefaultPVCSize   = "1Gi"
+
+	snapshotDir = "/snapshots"
+)
+
+// JobOptions describes the in-cluster deployment. Exactly one of PVC and
+// Upload says where snapshots go.
+type JobOptions struct {
+	Name      string
+	Namespace string
+	Image     string
+	// Schedule makes a CronJob; an empty schedule makes a one-off Job.
+	Schedule string
+
+	PVC          string
+	CreatePVC    bool
+	PVCSize      string
+	StorageClass string
+
+	Upload    string
+	UploadKey string
+	S3        storage.S3Config
+	// S3Secret names a Secret whose keys (AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY, ...) are passed to the container as
+	// environment variables.
+	S3Secret string
+
+	// CaptureArgs are extra capture flags, such as filters.
+	CaptureArgs []string
+}
+
+func (o *JobOptions) validate() error {
+	if o.Image == "" {
+		return fmt.Errorf("an image containing the plugin, kubectl and meshsync is required")
+	}
+	if (o.PVC == "") == (o.Upload == "") {
+		return fmt.Errorf("choose exactly one destination: a PVC or an S3 upload")
+	}
+	if o.Upload != "" {
+		if _, err := storage.ParseS3URL(o.Upload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JobManifests returns the ServiceAccount, RBAC, optional PVC and the Job or
+// CronJob that runs a capture with the pod's service account.
+func JobManifests(opts JobOptions) ([]map[string]interface{}, error) {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.PVCSize == "" {
+		opts.PVCSize = DefaultPVCSize
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]interface{}{"app.kubernetes.io/name": opts.Name}
+	metadata := func(namespaced bool) map[string]interface{} {
+		m := map[string]interface{}{"name": opts.Name, "labels": labels}
+		if namespaced {
+			m["namespace"] = opts.Namespace
+		}
+		return m
+	}
+
+	docs := []map[string]interface{}{
+		{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": opts.Namespace, "labels": labels},
+		},
+		{
+			"apiVersion": "v1",
+			"kind":       "ServiceAccount",
+			"metadata":   metadata(true),
+		},
+		{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata":   metadata(false),
+			"rules":      clusterRules(),
+		},
+		{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRoleBinding",
+			"metadata":   metadata(false),
+			"roleRef": map[string]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "ClusterRole",
+				"name":     opts.Name,
+			},
+			"subjects": []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": opts.Name, "namespace": opts.Namespace},
+			},
+		},
+	}
+
+	if opts.PVC != "" && opts.CreatePVC {
+		spec := map[string]interface{}{
+			"accessModes": []interface{}{"ReadWriteOnce"},
+			"resources":   map[string]interface{}{"requests": map[string]interface{}{"storage": opts.PVCSize}},
+		}
+		if opts.StorageClass != "" {
+			spec["storageClassName"] = opts.StorageClass
+		}
+		pvcMetadata := metadata(true)
+		pvcMetadata["name"] = opts.PVC
+		docs = append(docs, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata":   pvcMetadata,
+			"spec":       spec,
+		})
+	}
+
+	jobSpec := map[string]interface{}{
+		"backoffLimit": 1,
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+			"spec":     podSpec(opts),
+		},
+	}
+	if opts.Schedule == "" {
+		docs = append(docs, map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata":   metadata(true),
+			"spec":       jobSpec,
+		})
+	} else {
+		docs = append(docs, map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "CronJob",
+			"metadata":   metadata(true),
+			"spec": map[string]interface{}{
+				"schedule": opts.Schedule,
+				// Runs share the meshery namespace and CRDs, so they
+				// must not overlap.
+				"concurrencyPolicy":          "Forbid",
+				"successfulJobsHistoryLimit": 3,
+				"failedJobsHistoryLimit":     3,
+				"jobTemplate":                map[string]interface{}{"spec": jobSpec},
+			},
+		})
+	}
+	return docs, nil
+}
+
+// clusterRules lets the pod read the resources MeshSync watches, and
+// nothing else (Secrets in particular), and manage the CRDs, namespace and
+// instances a capture creates and removes.
+func clusterRules() []interface{} {
+	rule := func(groups, resources, verbs []interface{}) map[string]interface{} {
+		return map[string]interface{}{"apiGroups": groups, "resources": resources, "verbs": verbs}
+	}
+	read := []interface{}{"get", "list", "watch"}
+	create := []interface{}{"create"}
+	modify := []interface{}{"get", "update", "patch", "delete"}
+	// named limits a rule to the objects the capture creates. Kubernetes
+	// cannot restrict create by name, so create gets a rule of its own.
+	named := func(groups, resources, names []interface{}) map[string]interface{} {
+		r := rule(groups, resources, modify)
+		r["resourceNames"] = names
+		return r
+	}
+	manage := []interface{}{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+	var rules []interface{}
+	byGroup := make(map[string]int)
+	for _, watched := range crds.WatchedResources {
+		i, ok := byGroup[watched.Group]
+		if !ok {
+			i = len(rules)
+			byGroup[watched.Group] = i
+			rules = append(rules, rule([]interface{}{watched.Group}, nil, read))
+		}
+		readRule := rules[i].(map[string]interface{})
+		readRule["resources"] = append(readRule["resources"].([]interface{}), watched.Resource)
+	}
+	crdGroup := []interface{}{"apiextensions.k8s.io"}
+	crdResource := []interface{}{"customresourcedefinitions"}
+	namespaceResource := []interface{}{"namespaces"}
+	return append(rules,
+		rule(crdGroup, crdResource, create),
+		named(crdGroup, crdResource, []interface{}{"brokers.meshery.io", "meshsyncs.meshery.io"}),
+		rule([]interface{}{""}, namespaceResource, create),
+		named([]interface{}{""}, namespaceResource, []interface{}{"meshery"}),
+		rule([]interface{}{"meshery.io"}, []interface{}{"brokers", "meshsyncs"}, manage),
+	)
+}
+
+func podSpec(opts JobOptions) map[string]interface{} {
+	args := []interface{}{"--auto-name", "--output", snapshotDir + "/meshsync-snapshot.json", "--quiet"}
+	if opts.PVC != "" {
+		args = append(args, "--catalog", snapshotDir+"/catalog.db")
+	} else {
+		args = append(args, "--no-catalog", "--upload", opts.Upload)
+		if opts.UploadKey != "" {
+			args = append(args, "--upload-key", opts.UploadKey)
+		}
+		if opts.S3.Endpoint != "" {
+			args = append(args, "--s3-endpoint", opts.S3.Endpoint)
+		}
+		if opts.S3.Region != "" {
+			args = append(args, "--s3-region", opts.S3.Region)
+		}
+		if opts.S3.PathStyle {
+			args = append(args, "--s3-path-style")
+		}
+	}
+	for _, arg := range opts.CaptureArgs {
+		args = append(args, arg)
+	}
+
+	container := map[string]interface{}{
+		"name":            "capture",
+		"image":           opts.Image,
+		"imagePullPolicy": "IfNotPresent",
+		"args":            args,
+		"workingDir":      snapshotDir,
+		"volumeMounts": []interface{}{
+			map[string]interface{}{"name": "snapshots", "mountPath": snapshotDir},
+		},
+	}
+	if opts.S3Secret != "" {
+		container["envFrom"] = []interface{}{
+			map[string]interface{}{"secretRef": map[string]interface{}{"name": opts.S3Secret}},
+		}
+	}
+
+	volume := map[string]interface{}{"name": "snapshots", "emptyDir": map[string]interface{}{}}
+	if opts.PVC != "" {
+		volume = map[string]interface{}{
+			"name":                  "snapshots",
+			"persistentVolumeClaim": map[string]interface{}{"claimName": opts.PVC},
+		}
+	}
+
+	return map[string]interface{}{
+		"serviceAccountName": opts.Name,
+		"restartPolicy":      "Never",
+		// MeshSync reaches the embedded broker as nats:4222, which on a
+		// workstation comes from an /etc/hosts entry.
+		"hostAliases": []interface{}{
+			map[string]interface{}{"ip": "127.0.0.1", "hostnames": []interface{}{"nats"}},
+		},
+		"containers": []interface{}{container},
+		"volumes":    []interface{}{volume},
+	}
+}
+
+// WriteYAML renders manifests as a multi-document YAML stream.
+func WriteYAML(docs []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		var part bytes.Buffer
+		encoder := yaml.NewEncoder(&part)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+		encoder.Close()
+		buf.Write(part.Bytes())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+package deploy
+
+import "testing"
+
+func TestJobManifestsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    JobOptions
+		wantErr bool
+	}{
+		{name: "pvc", opts: JobOptions{Image: "plugin:latest", PVC: "snapshots"}},
+		{name: "upload", opts: JobOptions{Image: "plugin:latest", Upload: "s3://bucket/prefix"}},
+		{name: "no image", opts: JobOptions{PVC: "snapshots"}, wantErr: true},
+		{name: "no destination", opts: JobOptions{Image: "plugin:latest"}, wantErr: true},
+		{name: "both destinations", opts: JobOptions{Image: "plugin:latest", PVC: "snapshots", Upload: "s3://bucket"}, wantErr: true},
+		{name: "bad upload URL", opts: JobOptions{Image: "plugin:latest", Upload: "https://bucket"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JobManifests(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JobManifests error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobManifestsKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		opts JobOptions
+		want []string
+	}{
+		{
+			name: "one-off job",
+			opts: JobOptions{Image: "plugin:latest", Upload: "s3://bucket"},
+			want: []string{"Namespace", "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Job"},
+		},
+		{
+			name: "cron job with new pvc",
+			opts: JobOptions{Image: "plugin:latest", PVC: "snapshots", CreatePVC: true, Schedule: DefaultSchedule},
+			want: []string{"Namespace", "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "PersistentVolumeClaim", "CronJob"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := JobManifests(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, doc := range docs {
+				got = append(got, doc["kind"].(string))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("kinds = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("kinds = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterRulesLimitWrites(t *testing.T) {
+	allowedNames := map[string][]string{
+		"namespaces":                {"meshery"},
+		"customresourcedefinitions": {"brokers.meshery.io", "meshsyncs.meshery.io"},
+	}
+	creatable := make(map[string]bool)
+	for _, r := range clusterRules() {
+		rule := r.(map[string]interface{})
+		names, _ := rule["resourceNames"].([]interface{})
+		for _, resource := range rule["resources"].([]interface{}) {
+			resource := resource.(string)
+			if resource == "secrets" || resource == "*" {
+				t.Errorf("rule grants access to %s", resource)
+			}
+			allowed, limited := allowedNames[resource]
+			if !limited {
+				continue
+			}
+			for _, verb := range rule["verbs"].([]interface{}) {
+				switch verb {
+				case "get", "list", "watch":
+				case "create":
+					creatable[resource] = true
+				default:
+					if len(names) != len(allowed) {
+						t.Errorf("%s %s is not limited to %v (resourceNames %v)", verb, resource, allowed, names)
+						continue
+					}
+					for i := range allowed {
+						if names[i] != allowed[i] {
+							t.Errorf("%s %s is not limited to %v (resourceNames %v)", verb, resource, allowed, names)
+						}
+					}
+				}
+			}
+		}
+	}
+	for resource := range allowedNames {
+		if !creatable[resource] {
+			t.Errorf("no rule allows creating %s", resource)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+const (
+	designSchemaVersion       = "designs.meshery.io/v1beta1"
+	componentSchemaVersion    = "components.meshery.io/v1beta1"
+	relationshipSchemaVersion = "relationships.meshery.io/v1alpha3"
+	defaultKubernetesVersion  = "v1.0.0"
+)
+
+type Design struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	SchemaVersion string                   `json:"schemaVersion"`
+	Version       string                   `json:"version"`
+	Metadata      map[string]interface{}   `json:"metadata"`
+	Components    []map[string]interface{} `json:"components"`
+	Relationships []map[string]interface{} `json:"relationships"`
+}
+
+type DesignOptions struct {
+	Name string
+}
+
+// BuildDesign converts snapshot resources into a Meshery design.
+func BuildDesign(snap *models.Snapshot, opts DesignOptions) *Design {
+	name := designName(snap, opts)
+	design := &Design{
+		ID:            utils.NameUUID("design", snap.ClusterID, name),
+		Name:          name,
+		SchemaVersion: designSchemaVersion,
+		Version:       "0.0.1",
+		Metadata: map[string]interface{}{
+			"source":     "kubectl-meshsync_snapshot",
+			"cluster_id": snap.ClusterID,
+			"captured":   snap.Timestamp,
+		},
+		Components:    []map[string]interface{}{},
+		Relationships: []map[string]interface{}{},
+	}
+
+	componentIDs := make(map[string]string)
+	for _, mapped := range mapComponents(snap.Resources) {
+		componentIDs[graph.NodeID(mapped.resource)] = mapped.id
+		design.Components = append(design.Components, mapped.component)
+	}
+
+	design.Relationships = append(design.Relationships, designRelationships(snap.Resources, componentIDs)...)
+
+	return design
+}
+
+// FillDesignMetadata sets each resource's PatternResource and
+// ComponentMetadata to the component BuildDesign maps it to, so a saved
+// snapshot carries the same IDs as the design exported from it.
+func FillDesignMetadata(snap *models.Snapshot, opts DesignOptions) {
+	designID := utils.NameUUID("design", snap.ClusterID, designName(snap, opts))
+	for _, mapped := range mapComponents(snap.Resources) {
+		mapped.resource.PatternResource = mapped.component
+		mapped.resource.ComponentMetadata = map[string]interface{}{
+			"component":     mapped.resource.Kind,
+			"version":       mapped.resource.APIVersion,
+			"model":         mapped.model,
+			"model_version": mapped.modelVersion,
+			"design_id":     designID,
+		}
+	}
+}
+
+func designName(snap *models.Snapshot, opts DesignOptions) string {
+	if opts.Name != "" {
+		return opts.Name
+	}
+	return fmt.Sprintf("snapshot-%s", snap.ClusterID)
+}
+
+type mappedComponent struct {
+	resource     *models.KubernetesResource
+	id           string
+	component    map[string]interface{}
+	model        string
+	modelVersion string
+}
+
+func mapComponents(resources []*models.KubernetesResource) []mappedComponent {
+	kubernetesVersion := DetectKubernetesVersion(resources)
+	var mapped []mappedComponent
+	for _, resource := range resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		modelName, modelVersion := modelFor(resource, kubernetesVersion)
+		id := componentID(resource)
+		mapped = append(mapped, mappedComponent{
+			resource:     resource,
+			id:           id,
+			component:    buildComponent(resource, id, modelName, modelVersion),
+			model:        modelName,
+			modelVersion: modelVersion,
+		})
+	}
+	return mapped
+}
+
+func (d *Design) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal design: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func buildComponent(resource *models.KubernetesResource, id, modelName, modelVersion string) map[string]interface{} {
+	meta := resource.KubernetesResourceMeta
+
+	metadata := map[string]interface{}{
+		"name": meta.Name,
+	}
+	if meta.Namespace != "" {
+		metadata["namespace"] = meta.Namespace
+	}
+	if labels := meta.LabelMap(); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if annotations := meta.AnnotationMap(); len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+
+	configuration := map[string]interface{}{
+		"metadata": metadata,
+	}
+	if spec, err := resource.Spec.Decode(); err == nil && len(spec) > 0 {
+		configuration["spec"] = spec
+	}
+	if resource.Data != "" {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(resource.Data), &data); err == nil {
+			configuration["data"] = data
+		}
+	}
+
+	return map[string]interface{}{
+		"id":            id,
+		"schemaVersion": componentSchemaVersion,
+		"version":       "v1.0.0",
+		"displayName":   meta.Name,
+		"component": map[string]interface{}{
+			"kind":    resource.Kind,
+			"version": resource.APIVersion,
+		},
+		"model": map[string]interface{}{
+			"name":    modelName,
+			"version": modelVersion,
+			"model": map[string]interface{}{
+				"version": modelVersion,
+			},
+		},
+		"configuration": configuration,
+	}
+}
+
+func designRelationships(resources []*models.KubernetesResource, componentIDs map[string]string) []map[string]interface{} {
+	g := graph.Build(resources)
+
+	var relationships []map[string]interface{}
+	for _, edge := range g.Edges {
+		from, _ := g.Node(edge.From)
+		to, _ := g.Node(edge.To)
+
+		switch edge.Type {
+		case graph.EdgeOwns:
+			relationships = append(relationships, relationship("hierarchical", "parent", "inventory",
+				componentIDs[edge.From], from.Kind, componentIDs[edge.To], to.Kind))
+		case graph.EdgeSelects:
+			relationships = append(relationships, relationship("edge", "non-binding", "network",
+				componentIDs[edge.From], from.Kind, componentIDs[edge.To], to.Kind))
+		}
+	}
+	return relationships
+}
+
+func relationship(kind, relType, subType, fromID, fromKind, toID, toKind string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            utils.NameUUID("relationship", kind, subType, fromID, toID),
+		"schemaVersion": relationshipSchemaVersion,
+		"kind":          kind,
+		"type":          relType,
+		"subType":       subType,
+		"status":        "approved",
+		"selectors": []interface{}{
+			map[string]interface{}{
+				"allow": map[string]interface{}{
+					"from": []interface{}{map[string]interface{}{"id": fromID, "kind": fromKind}},
+					"to":   []interface{}{map[string]interface{}{"id": toID, "kind": toKind}},
+				},
+			},
+		},
+	}
+}
+
+func modelFor(resource *models.KubernetesResource, kubernetesVersion string) (string, string) {
+	if resource.Model != "" {
+		return resource.Model, kubernetesVersion
+	}
+
+	group := ""
+	if idx := strings.Index(resource.APIVersion, "/"); idx >= 0 {
+		group = resource.APIVersion[:idx]
+	}
+
+	if group == "" || !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io") {
+		return "kubernetes", kubernetesVersion
+	}
+	version := resource.APIVersion[strings.Index(resource.APIVersion, "/")+1:]
+	return group, version
+}
+
+// DetectKubernetesVersion returns the lowest kubelet version reported by the
+// snapshot's Nodes, falling back to a default when there are none.
+func DetectKubernetesVersion(resources []*models.KubernetesResource) string {
+	lowest := ""
+	for _, resource := range resources {
+		if resource == nil || resource.Kind != "Node" {
+			continue
+		}
+		status, err := resource.Status.Decode()
+		if err != nil {
+			continue
+		}
+		nodeInfo, _ := status["nodeInfo"].(map[string]interface{})
+		version, ok := nodeInfo["kubeletVersion"].(string)
+		if !ok || version == "" {
+			continue
+		}
+		if lowest == "" || compareKubeletVersions(version, lowest) < 0 {
+			lowest = version
+		}
+	}
+
+	if lowest == "" {
+		return defaultKubernetesVersion
+	}
+	return lowest
+}
+
+// compareKubeletVersions orders versions such as "v1.9.0" and
+// "v1.28.3-eks-1234" by their numeric major, minor and patch parts, so
+// that v1.9 sorts before v1.28.
+func compareKubeletVersions(a, b string) int {
+	partsA, partsB := versionNumbers(a), versionNumbers(b)
+	for i := range partsA {
+		if partsA[i] != partsB[i] {
+			if partsA[i] < partsB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func versionNumbers(version string) [3]int {
+	var numbers [3]int
+	version = strings.TrimPrefix(version, "v")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	for i, part := range strings.SplitN(version, ".", 3) {
+		numbers[i], _ = strconv.Atoi(part)
+	}
+	return numbers
+}
+
+func componentID(resource *models.KubernetesResource) string {
+	if uid := resource.UID(); uid != "" {
+		return uid
+	}
+	return utils.NameUUID("component", resource.APIVersion, resource.Kind, resource.Namespace(), resource.Name())
+}
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func node(kubeletVersion string) *models.KubernetesResource {
+	return &models.KubernetesResource{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Status: &models.KubernetesResourceStatus{
+			Attribute: fmt.Sprintf(`{"nodeInfo":{"kubeletVersion":%q}}`, kubeletVersion),
+		},
+	}
+}
+
+func TestDetectKubernetesVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "no nodes", want: defaultKubernetesVersion},
+		{name: "single", versions: []string{"v1.29.2"}, want: "v1.29.2"},
+		{name: "minor compared numerically", versions: []string{"v1.28.3", "v1.9.11"}, want: "v1.9.11"},
+		{name: "patch compared numerically", versions: []string{"v1.28.10", "v1.28.9"}, want: "v1.28.9"},
+		{name: "vendor suffix", versions: []string{"v1.30.0-eks-036c24b", "v1.27.16-eks-a737599"}, want: "v1.27.16-eks-a737599"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resources []*models.KubernetesResource
+			for _, version := range tt.versions {
+				resources = append(resources, node(version))
+			}
+			if got := DetectKubernetesVersion(resources); got != tt.want {
+				t.Errorf("DetectKubernetesVersion(%v) = %q, want %q", tt.versions, got, tt.want)
+			}
+		})
+	}
+}
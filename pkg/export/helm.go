@@ -0,0 +1,242 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+type chartMetadata struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion"`
+}
+
+// Template expressions are written with a marker so they can be told apart
+// from literal braces in the captured data, then unquoted after marshalling:
+// a quoted "{{ .Values.x.replicas }}" would render as a string.
+const templateMarker = "__helm_template__"
+
+var markedTemplate = regexp.MustCompile(`'?` + templateMarker + `([^'\n]*)'?`)
+
+// WriteHelmChart generates a chart skeleton for a namespace. Container
+// images, replica counts and literal env values are lifted into values.yaml,
+// under the workload's kind and name, and referenced from the templates.
+func WriteHelmChart(resources []*models.KubernetesResource, namespace, chartName, dir string) (int, error) {
+	selected := NamespaceBaseResources(resources, namespace)
+	if len(selected) == 0 {
+		return 0, fmt.Errorf("no workloads, Services or ConfigMaps found in namespace %q", namespace)
+	}
+	if chartName == "" {
+		chartName = namespace
+	}
+
+	templatesDir := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	usedKeys := map[string]map[string]bool{}
+	for _, resource := range selected {
+		manifest, err := BuildManifest(resource)
+		if err != nil {
+			return 0, err
+		}
+		delete(manifest.Metadata, "namespace")
+		kindKey := valuesKey(resource.Kind)
+		if usedKeys[kindKey] == nil {
+			usedKeys[kindKey] = map[string]bool{}
+		}
+		// Names such as "web-api" and "web.api" share a key.
+		key := uniqueKey(usedKeys[kindKey], valuesKey(resource.Name()))
+		if workloadValues := parameterizeWorkload(resource.Kind, kindKey+"."+key, manifest.Spec); len(workloadValues) > 0 {
+			kindValues, _ := values[kindKey].(map[string]interface{})
+			if kindValues == nil {
+				kindValues = map[string]interface{}{}
+				values[kindKey] = kindValues
+			}
+			kindValues[key] = workloadValues
+		}
+
+		escapeTemplateBraces(manifest.Metadata)
+		escapeTemplateBraces(manifest.Spec)
+		escapeTemplateBraces(manifest.Data)
+		escapeTemplateBraces(manifest.StringData)
+
+		data, err := manifest.Marshal()
+		if err != nil {
+			return 0, err
+		}
+		data = markedTemplate.ReplaceAll(data, []byte("$1"))
+
+		fileName := baseFileName(resource)
+		if err := os.WriteFile(filepath.Join(templatesDir, fileName), data, 0644); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", fileName, err)
+		}
+	}
+
+	chart := chartMetadata{
+		APIVersion:  "v2",
+		Name:        chartName,
+		Description: fmt.Sprintf("Generated from the %s namespace of a MeshSync snapshot", namespace),
+		Type:        "application",
+		Version:     "0.1.0",
+		AppVersion:  "1.0.0",
+	}
+	if err := writeYAMLFile(filepath.Join(dir, "Chart.yaml"), chart); err != nil {
+		return 0, err
+	}
+	if err := writeYAMLFile(filepath.Join(dir, "values.yaml"), values); err != nil {
+		return 0, err
+	}
+
+	return len(selected), nil
+}
+
+func parameterizeWorkload(kind, key string, spec map[string]interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	prefix := ".Values." + key
+
+	if replicas, ok := spec["replicas"]; ok && (kind == "Deployment" || kind == "StatefulSet") {
+		values["replicas"] = replicas
+		spec["replicas"] = templateValue("{{ %s.replicas }}", prefix)
+	}
+
+	podSpec := utils.PodSpec(kind, spec)
+	if podSpec == nil {
+		return values
+	}
+
+	containerValues := map[string]interface{}{}
+	usedKeys := map[string]bool{}
+	for _, container := range utils.Containers(podSpec, "initContainers", "containers") {
+		name, _ := container["name"].(string)
+		image, _ := container["image"].(string)
+		if name == "" || image == "" {
+			continue
+		}
+		containerKey := uniqueKey(usedKeys, valuesKey(name))
+		containerPrefix := fmt.Sprintf("%s.containers.%s", prefix, containerKey)
+
+		ref := utils.ParseImageReference(image)
+		imageValues := map[string]interface{}{"repository": utils.ImageName(image)}
+		if ref.Digest != "" {
+			imageValues["digest"] = ref.Digest
+			container["image"] = templateValue("{{ %[1]s.image.repository }}@{{ %[1]s.image.digest }}", containerPrefix)
+		} else {
+			imageValues["tag"] = ref.Tag
+			container["image"] = templateValue("{{ %[1]s.image.repository }}:{{ %[1]s.image.tag }}", containerPrefix)
+		}
+		current := map[string]interface{}{"image": imageValues}
+
+		envValues := map[string]interface{}{}
+		env, _ := container["env"].([]interface{})
+		for _, item := range env {
+			variable, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			envName, _ := variable["name"].(string)
+			value, hasValue := variable["value"]
+			if envName == "" || !hasValue {
+				continue
+			}
+			envValues[envName] = value
+			variable["value"] = templateValue("{{ index %s.env %q | quote }}", containerPrefix, envName)
+		}
+		if len(envValues) > 0 {
+			current["env"] = envValues
+		}
+
+		containerValues[containerKey] = current
+	}
+
+	if len(containerValues) > 0 {
+		values["containers"] = containerValues
+	}
+	return values
+}
+
+func templateValue(format string, args ...interface{}) string {
+	return templateMarker + fmt.Sprintf(format, args...)
+}
+
+// escapeTemplateBraces keeps literal "{{" in captured data (alerting rules,
+// config templates) from being evaluated by Helm.
+func escapeTemplateBraces(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, templateMarker) {
+			return v
+		}
+		return strings.ReplaceAll(v, "{{", `{{ "{{" }}`)
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = escapeTemplateBraces(child)
+		}
+	case map[string]string:
+		for key, child := range v {
+			v[key] = escapeTemplateBraces(child).(string)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = escapeTemplateBraces(child)
+		}
+	}
+	return value
+}
+
+// valuesKey turns a Kubernetes name such as "web-frontend" into a key that
+// can be used in dotted template paths ("webFrontend").
+func valuesKey(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i == 0 {
+			b.WriteString(strings.ToLower(part[:1]) + part[1:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	key := b.String()
+	if key == "" || unicode.IsDigit(rune(key[0])) {
+		key = "r" + key
+	}
+	return key
+}
+
+// uniqueKey returns key, or key with the first free numeric suffix if it is
+// already used, and marks the result as used.
+func uniqueKey(used map[string]bool, key string) string {
+	unique := key
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", key, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func writeYAMLFile(path string, value interface{}) error {
+	data, err := marshalYAML(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func workload(kind, name, image string) *models.KubernetesResource {
+	return &models.KubernetesResource{
+		APIVersion:             "apps/v1",
+		Kind:                   kind,
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: name, Namespace: "shop"},
+		Spec: &models.KubernetesResourceSpec{
+			Attribute: `{"replicas":2,"template":{"spec":{"containers":[{"name":"app","image":"` + image + `"}]}}}`,
+		},
+	}
+}
+
+func TestWriteHelmChartValuesKeys(t *testing.T) {
+	resources := []*models.KubernetesResource{
+		workload("Deployment", "web", "nginx:1.25"),
+		workload("StatefulSet", "web", "postgres:16"),
+		workload("Deployment", "web-api", "api:1"),
+		workload("Deployment", "web.api", "api:2"),
+	}
+	dir := t.TempDir()
+	if _, err := WriteHelmChart(resources, "shop", "", dir); err != nil {
+		t.Fatalf("WriteHelmChart: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "values.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values map[string]map[string]struct {
+		Containers map[string]struct {
+			Image struct {
+				Repository string `yaml:"repository"`
+			} `yaml:"image"`
+		} `yaml:"containers"`
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		t.Fatalf("values.yaml: %v", err)
+	}
+	images := map[string]string{}
+	for kind, workloads := range values {
+		for name, v := range workloads {
+			images[kind+"."+name] = v.Containers["app"].Image.Repository
+		}
+	}
+	want := map[string]string{
+		"deployment.web":     "nginx",
+		"statefulSet.web":    "postgres",
+		"deployment.webApi":  "api",
+		"deployment.webApi2": "api",
+	}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("values = %v, want %v", images, want)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "deployment-web.yaml", want: ".Values.deployment.web.replicas"},
+		{file: "statefulset-web.yaml", want: ".Values.statefulSet.web.replicas"},
+		{file: "deployment-web-api.yaml", want: ".Values.deployment.webApi.containers.app.image"},
+		{file: "deployment-web.api.yaml", want: ".Values.deployment.webApi2.containers.app.image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			template, err := os.ReadFile(filepath.Join(dir, "templates", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(template), tt.want) {
+				t.Errorf("%s does not reference %s:\n%s", tt.file, tt.want, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+var baseKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"CronJob":     true,
+	"Job":         true,
+	"Service":     true,
+	"ConfigMap":   true,
+}
+
+type kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Namespace  string   `yaml:"namespace"`
+	Resources  []string `yaml:"resources"`
+}
+
+// NamespaceBaseResources selects the workloads, Services and ConfigMaps of a
+// namespace that someone would have applied by hand: controller-owned objects
+// and objects the control plane creates in every namespace are left out.
+func NamespaceBaseResources(resources []*models.KubernetesResource, namespace string) []*models.KubernetesResource {
+	var selected []*models.KubernetesResource
+	for _, resource := range resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		if !baseKinds[resource.Kind] || resource.Namespace() != namespace {
+			continue
+		}
+		if resource.IsControllerOwned() || isControlPlaneGenerated(resource) {
+			continue
+		}
+		selected = append(selected, resource)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return baseFileName(selected[i]) < baseFileName(selected[j])
+	})
+	return selected
+}
+
+func WriteKustomizeBase(resources []*models.KubernetesResource, namespace, dir string) (int, error) {
+	selected := NamespaceBaseResources(resources, namespace)
+	if len(selected) == 0 {
+		return 0, fmt.Errorf("no workloads, Services or ConfigMaps found in namespace %q", namespace)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	base := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  namespace,
+	}
+
+	for _, resource := range selected {
+		manifest, err := BuildManifest(resource)
+		if err != nil {
+			return 0, err
+		}
+		delete(manifest.Metadata, "namespace")
+
+		data, err := manifest.Marshal()
+		if err != nil {
+			return 0, err
+		}
+
+		fileName := baseFileName(resource)
+		if err := os.WriteFile(filepath.Join(dir, fileName), data, 0644); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", fileName, err)
+		}
+		base.Resources = append(base.Resources, fileName)
+	}
+
+	data, err := marshalYAML(base)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal kustomization: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write kustomization.yaml: %w", err)
+	}
+
+	return len(selected), nil
+}
+
+func baseFileName(resource *models.KubernetesResource) string {
+	return fmt.Sprintf("%s-%s.yaml", strings.ToLower(resource.Kind), resource.Name())
+}
+
+func isControlPlaneGenerated(resource *models.KubernetesResource) bool {
+	switch {
+	case resource.Kind == "ConfigMap" && resource.Name() == "kube-root-ca.crt":
+		return true
+	case resource.Kind == "Service" && resource.Name() == "kubernetes" && resource.Namespace() == "default":
+		return true
+	}
+	return false
+}
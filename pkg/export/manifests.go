@@ -0,0 +1,289 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+type Manifest struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   map[string]interface{} `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec,omitempty"`
+	Type       string                 `yaml:"type,omitempty"`
+	Immutable  *bool                  `yaml:"immutable,omitempty"`
+	Data       map[string]interface{} `yaml:"data,omitempty"`
+	BinaryData map[string]interface{} `yaml:"binaryData,omitempty"`
+	StringData map[string]interface{} `yaml:"stringData,omitempty"`
+}
+
+type ManifestOptions struct {
+	OnlyOwnedRoots bool
+}
+
+var generatedAnnotations = map[string]bool{
+	"kubectl.kubernetes.io/last-applied-configuration": true,
+	"deployment.kubernetes.io/revision":                true,
+	"deprecated.daemonset.template.generation":         true,
+}
+
+// Spec fields that the API server or controllers assign and that would be
+// rejected or reassigned on apply. A Service's clusterIP of "None" is chosen
+// by the user and is kept, as it makes the Service headless.
+var generatedSpecFields = map[string][]string{
+	"Service":               {"clusterIP", "clusterIPs"},
+	"Pod":                   {"nodeName"},
+	"PersistentVolumeClaim": {"volumeName"},
+}
+
+func BuildManifest(resource *models.KubernetesResource) (*Manifest, error) {
+	if resource == nil || resource.KubernetesResourceMeta == nil {
+		return nil, fmt.Errorf("resource has no metadata")
+	}
+	meta := resource.KubernetesResourceMeta
+
+	metadata := map[string]interface{}{
+		"name": meta.Name,
+	}
+	if meta.Namespace != "" {
+		metadata["namespace"] = meta.Namespace
+	}
+	if labels := meta.LabelMap(); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	annotations := meta.AnnotationMap()
+	for key := range annotations {
+		if generatedAnnotations[key] {
+			delete(annotations, key)
+		}
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+
+	spec, err := resource.Spec.Decode()
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", resource.Kind, meta.Name, err)
+	}
+	for _, field := range generatedSpecFields[resource.Kind] {
+		if !isHeadless(spec[field]) {
+			delete(spec, field)
+		}
+	}
+	switch resource.Kind {
+	case "Pod":
+		stripServiceAccountVolumes(spec)
+	case "Job":
+		stripJobSelector(spec)
+	}
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		if templateMeta, ok := template["metadata"].(map[string]interface{}); ok && templateMeta["creationTimestamp"] == nil {
+			delete(templateMeta, "creationTimestamp")
+		}
+	}
+
+	manifest := &Manifest{
+		APIVersion: resource.APIVersion,
+		Kind:       resource.Kind,
+		Metadata:   metadata,
+		Spec:       spec,
+		Type:       resource.Type,
+	}
+
+	if resource.Immutable != "" {
+		immutable := resource.Immutable == "true"
+		manifest.Immutable = &immutable
+	}
+	if manifest.Data, err = decodeDataField(resource.Data, "data"); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", resource.Kind, meta.Name, err)
+	}
+	if manifest.BinaryData, err = decodeDataField(resource.BinaryData, "binaryData"); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", resource.Kind, meta.Name, err)
+	}
+	if manifest.StringData, err = decodeDataField(resource.StringData, "stringData"); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", resource.Kind, meta.Name, err)
+	}
+
+	return manifest, nil
+}
+
+func isHeadless(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == "None"
+	case []interface{}:
+		return len(v) == 1 && v[0] == "None"
+	}
+	return false
+}
+
+// jobControllerLabels are added by the Job controller to tie the pod
+// template to the Job's UID; a re-created Job gets a new UID and the API
+// server rejects a template that still carries the old one.
+var jobControllerLabels = []string{"controller-uid", "batch.kubernetes.io/controller-uid"}
+
+func stripJobSelector(spec map[string]interface{}) {
+	if manual, _ := spec["manualSelector"].(bool); manual {
+		return
+	}
+	delete(spec, "selector")
+	template, _ := spec["template"].(map[string]interface{})
+	templateMeta, _ := template["metadata"].(map[string]interface{})
+	labels, ok := templateMeta["labels"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, label := range jobControllerLabels {
+		delete(labels, label)
+	}
+	if len(labels) == 0 {
+		delete(templateMeta, "labels")
+	}
+}
+
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := marshalYAML(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s/%v: %w", m.Kind, m.Metadata["name"], err)
+	}
+	return data, nil
+}
+
+func marshalYAML(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteManifests writes one YAML file per resource under
+// <dir>/<namespace>/<kind>/<name>.yaml; cluster-scoped resources go under
+// <dir>/_cluster. Nodes and objects the control plane creates itself are
+// skipped, since applying them would fight the cluster. It returns the
+// number of files written.
+func WriteManifests(resources []*models.KubernetesResource, dir string, opts ManifestOptions) (int, error) {
+	written := 0
+	for _, resource := range resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		if resource.Kind == "Node" || isControlPlaneGenerated(resource) {
+			continue
+		}
+		if opts.OnlyOwnedRoots && resource.IsControllerOwned() {
+			continue
+		}
+
+		manifest, err := BuildManifest(resource)
+		if err != nil {
+			return written, err
+		}
+		data, err := manifest.Marshal()
+		if err != nil {
+			return written, err
+		}
+
+		path, err := ManifestPath(dir, resource)
+		if err != nil {
+			return written, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return written, fmt.Errorf("failed to write manifest: %w", err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// ManifestPath returns where WriteManifests puts a resource. Names come
+// from the snapshot, so any that could escape dir are rejected.
+func ManifestPath(dir string, resource *models.KubernetesResource) (string, error) {
+	namespace := resource.Namespace()
+	if resource.Kind == "" || resource.Name() == "" {
+		return "", fmt.Errorf("refusing to write a resource without a kind and name")
+	}
+	for _, segment := range []string{namespace, resource.Kind, resource.Name()} {
+		if strings.ContainsAny(segment, `/\`) || strings.Contains(segment, "..") {
+			return "", fmt.Errorf("refusing to write %s %s/%s: unsafe path segment %q", resource.Kind, namespace, resource.Name(), segment)
+		}
+	}
+	if namespace == "" {
+		namespace = "_cluster"
+	}
+	return filepath.Join(dir, namespace, strings.ToLower(resource.Kind), resource.Name()+".yaml"), nil
+}
+
+func stripServiceAccountVolumes(spec map[string]interface{}) {
+	isInjected := func(name interface{}) bool {
+		s, _ := name.(string)
+		return strings.HasPrefix(s, "kube-api-access-")
+	}
+
+	if volumes, ok := spec["volumes"].([]interface{}); ok {
+		var kept []interface{}
+		for _, v := range volumes {
+			if volume, ok := v.(map[string]interface{}); ok && isInjected(volume["name"]) {
+				continue
+			}
+			kept = append(kept, v)
+		}
+		if len(kept) == 0 {
+			delete(spec, "volumes")
+		} else {
+			spec["volumes"] = kept
+		}
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := spec[field].([]interface{})
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mounts, ok := container["volumeMounts"].([]interface{})
+			if !ok {
+				continue
+			}
+			var kept []interface{}
+			for _, m := range mounts {
+				if mount, ok := m.(map[string]interface{}); ok && isInjected(mount["name"]) {
+					continue
+				}
+				kept = append(kept, m)
+			}
+			if len(kept) == 0 {
+				delete(container, "volumeMounts")
+			} else {
+				container["volumeMounts"] = kept
+			}
+		}
+	}
+}
+
+func decodeDataField(value, field string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", field, err)
+	}
+	return data, nil
+}
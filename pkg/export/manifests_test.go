@@ -0,0 +1,126 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestBuildManifestSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		spec string
+		want map[string]interface{}
+	}{
+		{
+			name: "service cluster IP removed",
+			kind: "Service",
+			spec: `{"clusterIP":"10.0.0.1","clusterIPs":["10.0.0.1"],"type":"ClusterIP"}`,
+			want: map[string]interface{}{"type": "ClusterIP"},
+		},
+		{
+			name: "headless service kept",
+			kind: "Service",
+			spec: `{"clusterIP":"None","clusterIPs":["None"]}`,
+			want: map[string]interface{}{"clusterIP": "None", "clusterIPs": []interface{}{"None"}},
+		},
+		{
+			name: "job selector and controller labels removed",
+			kind: "Job",
+			spec: `{"selector":{"matchLabels":{"controller-uid":"abc"}},"template":{"metadata":{"labels":{"controller-uid":"abc","batch.kubernetes.io/controller-uid":"abc","job-name":"migrate"}}}}`,
+			want: map[string]interface{}{"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"job-name": "migrate"}}}},
+		},
+		{
+			name: "job with only controller labels",
+			kind: "Job",
+			spec: `{"selector":{},"template":{"metadata":{"labels":{"controller-uid":"abc"}}}}`,
+			want: map[string]interface{}{"template": map[string]interface{}{"metadata": map[string]interface{}{}}},
+		},
+		{
+			name: "manual job selector kept",
+			kind: "Job",
+			spec: `{"manualSelector":true,"selector":{"matchLabels":{"app":"x"}}}`,
+			want: map[string]interface{}{"manualSelector": true, "selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "x"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &models.KubernetesResource{
+				APIVersion:             "v1",
+				Kind:                   tt.kind,
+				KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: "example", Namespace: "default"},
+				Spec:                   &models.KubernetesResourceSpec{Attribute: tt.spec},
+			}
+			manifest, err := BuildManifest(resource)
+			if err != nil {
+				t.Fatalf("BuildManifest: %v", err)
+			}
+			if !reflect.DeepEqual(manifest.Spec, tt.want) {
+				t.Errorf("spec = %#v, want %#v", manifest.Spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		namespace string
+		resource  string
+		want      string
+		wantErr   bool
+	}{
+		{name: "namespaced", kind: "Deployment", namespace: "shop", resource: "web", want: "out/shop/deployment/web.yaml"},
+		{name: "cluster scoped", kind: "ClusterRole", resource: "view", want: "out/_cluster/clusterrole/view.yaml"},
+		{name: "dots in name", kind: "ConfigMap", namespace: "shop", resource: "app.config", want: "out/shop/configmap/app.config.yaml"},
+		{name: "parent in name", kind: "ConfigMap", namespace: "shop", resource: "..", wantErr: true},
+		{name: "slash in name", kind: "ConfigMap", namespace: "shop", resource: "../../etc/passwd", wantErr: true},
+		{name: "backslash in name", kind: "ConfigMap", namespace: "shop", resource: `a\b`, wantErr: true},
+		{name: "slash in kind", kind: "../Secret", namespace: "shop", resource: "web", wantErr: true},
+		{name: "parent namespace", kind: "Pod", namespace: "..", resource: "web", wantErr: true},
+		{name: "no name", kind: "Pod", namespace: "shop", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &models.KubernetesResource{
+				Kind:                   tt.kind,
+				KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: tt.resource, Namespace: tt.namespace},
+			}
+			got, err := ManifestPath("out", resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ManifestPath error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ManifestPath = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteManifestsSkipsClusterOwnedObjects(t *testing.T) {
+	resource := func(apiVersion, kind, namespace, name string) *models.KubernetesResource {
+		return &models.KubernetesResource{
+			APIVersion:             apiVersion,
+			Kind:                   kind,
+			KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: name, Namespace: namespace},
+		}
+	}
+	resources := []*models.KubernetesResource{
+		resource("v1", "Node", "", "node-1"),
+		resource("v1", "ConfigMap", "shop", "kube-root-ca.crt"),
+		resource("v1", "Service", "default", "kubernetes"),
+		resource("v1", "ConfigMap", "shop", "settings"),
+		resource("v1", "Namespace", "", "shop"),
+	}
+	dir := t.TempDir()
+	written, err := WriteManifests(resources, dir, ManifestOptions{})
+	if err != nil {
+		t.Fatalf("WriteManifests: %v", err)
+	}
+	if written != 2 {
+		t.Errorf("wrote %d manifests, want 2 (the settings ConfigMap and the Namespace)", written)
+	}
+}
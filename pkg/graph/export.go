@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatGraphML = "graphml"
+)
+
+// ValidFormat reports whether Write supports format.
+func ValidFormat(format string) bool {
+	return format == FormatDOT || format == FormatMermaid || format == FormatGraphML
+}
+
+func Write(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatMermaid:
+		return WriteMermaid(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	default:
+		return fmt.Errorf("unsupported graph format %q (use dot, mermaid or graphml)", format)
+	}
+}
+
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph snapshot {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	for _, node := range g.Nodes {
+		label := node.Kind + "\\n" + node.Name
+		if node.Namespace != "" {
+			label = node.Kind + "\\n" + node.Namespace + "/" + node.Name
+		}
+		fmt.Fprintf(&b, "  %q [label=\"%s\"];\n", node.ID, escapeDOT(label))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Type)
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func WriteMermaid(w io.Writer, g *Graph) error {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		name := node.Name
+		if node.Namespace != "" {
+			name = node.Namespace + "/" + node.Name
+		}
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n", ids[node.ID], escapeMermaid(node.Kind), escapeMermaid(name))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[edge.From], edge.Type, ids[edge.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "namespace", For: "node", AttrName: "namespace", AttrType: "string"},
+			{ID: "type", For: "edge", AttrName: "type", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "snapshot", EdgeDefault: "directed"},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.ID,
+			Data: []graphMLData{
+				{Key: "kind", Value: node.Kind},
+				{Key: "name", Value: node.Name},
+				{Key: "namespace", Value: node.Namespace},
+			},
+		})
+	}
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.From,
+			Target: edge.To,
+			Data:   []graphMLData{{Key: "type", Value: edge.Type}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode GraphML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func escapeDOT(s string) string {
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+func escapeMermaid(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
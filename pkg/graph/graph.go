@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+const (
+	EdgeOwns      = "owns"
+	EdgeSelects   = "selects"
+	EdgeRunsOn    = "runs-on"
+	EdgeMounts    = "mounts"
+	EdgeEnvFrom   = "env-from"
+	EdgeReference = "references"
+)
+
+type Node struct {
+	ID        string
+	Kind      string
+	Name      string
+	Namespace string
+	Resource  *models.KubernetesResource
+}
+
+type Edge struct {
+	From string
+	To   string
+	Type string
+}
+
+type Graph struct {
+	Nodes []*Node
+	Edges []Edge
+
+	nodes map[string]*Node
+	edges map[Edge]bool
+}
+
+func (n *Node) Label() string {
+	if n.Namespace == "" {
+		return fmt.Sprintf("%s/%s", n.Kind, n.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", n.Kind, n.Namespace, n.Name)
+}
+
+func NodeID(resource *models.KubernetesResource) string {
+	if uid := resource.UID(); uid != "" {
+		return uid
+	}
+	return Key(resource.Kind, resource.Namespace(), resource.Name())
+}
+
+func Key(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// Build links snapshot resources through owner references, Service label
+// selectors, Pod node assignments and Pod ConfigMap/Secret references.
+// Edges are only added when both ends are present in the snapshot.
+func Build(resources []*models.KubernetesResource) *Graph {
+	g := &Graph{
+		nodes: make(map[string]*Node),
+		edges: make(map[Edge]bool),
+	}
+
+	byKey := make(map[string]*Node)
+	for _, resource := range resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		node := &Node{
+			ID:        NodeID(resource),
+			Kind:      resource.Kind,
+			Name:      resource.Name(),
+			Namespace: resource.Namespace(),
+			Resource:  resource,
+		}
+		if _, exists := g.nodes[node.ID]; exists {
+			continue
+		}
+		g.nodes[node.ID] = node
+		g.Nodes = append(g.Nodes, node)
+		byKey[Key(node.Kind, node.Namespace, node.Name)] = node
+	}
+
+	link := func(from *Node, kind, namespace, name, edgeType string) {
+		if target, ok := byKey[Key(kind, namespace, name)]; ok {
+			g.addEdge(from.ID, target.ID, edgeType)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		resource := node.Resource
+
+		refs, _ := resource.KubernetesResourceMeta.DecodeOwnerReferences()
+		for _, ref := range refs {
+			if _, ok := g.nodes[ref.UID]; ok {
+				g.addEdge(ref.UID, node.ID, EdgeOwns)
+			}
+		}
+
+		spec, err := resource.Spec.Decode()
+		if err != nil {
+			continue
+		}
+
+		switch resource.Kind {
+		case "Service":
+			selector := utils.StringMap(spec["selector"])
+			for _, pod := range g.Nodes {
+				if pod.Kind == "Pod" && pod.Namespace == node.Namespace &&
+					utils.MatchesSelector(selector, pod.Resource.KubernetesResourceMeta.LabelMap()) {
+					g.addEdge(node.ID, pod.ID, EdgeSelects)
+				}
+			}
+		case "Pod":
+			if nodeName, ok := spec["nodeName"].(string); ok && nodeName != "" {
+				link(node, "Node", "", nodeName, EdgeRunsOn)
+			}
+			for _, ref := range PodReferences(spec) {
+				link(node, ref.Kind, node.Namespace, ref.Name, ref.EdgeType)
+			}
+		}
+	}
+
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Label() < g.Nodes[j].Label()
+	})
+	return g
+}
+
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, ok := g.nodes[id]
+	return node, ok
+}
+
+func (g *Graph) addEdge(from, to, edgeType string) {
+	edge := Edge{From: from, To: to, Type: edgeType}
+	if g.edges[edge] {
+		return
+	}
+	g.edges[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// Scope returns the subgraph of resources in namespace, plus cluster-scoped
+// resources (such as Nodes) that are directly linked to them.
+func (g *Graph) Scope(namespace string) *Graph {
+	if namespace == "" {
+		return g
+	}
+
+	keep := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.Namespace == namespace {
+			keep[node.ID] = true
+		}
+	}
+	for _, edge := range g.Edges {
+		from, to := g.nodes[edge.From], g.nodes[edge.To]
+		if keep[from.ID] && to.Namespace == "" {
+			keep[to.ID] = true
+		}
+		if keep[to.ID] && from.Namespace == "" {
+			keep[from.ID] = true
+		}
+	}
+
+	scoped := &Graph{
+		nodes: make(map[string]*Node),
+		edges: make(map[Edge]bool),
+	}
+	for _, node := range g.Nodes {
+		if keep[node.ID] {
+			scoped.nodes[node.ID] = node
+			scoped.Nodes = append(scoped.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			scoped.addEdge(edge.From, edge.To, edge.Type)
+		}
+	}
+	return scoped
+}
@@ -0,0 +1,84 @@
+package graph
+
+import "github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+
+type Reference struct {
+	Kind     string
+	Name     string
+	EdgeType string
+	Optional bool
+}
+
+// PodReferences lists the ConfigMaps, Secrets, PersistentVolumeClaims and
+// ServiceAccount a decoded pod spec refers to through volumes, envFrom, env
+// valueFrom and serviceAccountName.
+func PodReferences(podSpec map[string]interface{}) []Reference {
+	var refs []Reference
+	add := func(kind, name, edgeType string, optional bool) {
+		if name != "" {
+			refs = append(refs, Reference{Kind: kind, Name: name, EdgeType: edgeType, Optional: optional})
+		}
+	}
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, item := range volumes {
+		volume, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		add("ConfigMap", stringField(volume, "configMap", "name"), EdgeMounts, optionalField(volume, "configMap"))
+		add("Secret", stringField(volume, "secret", "secretName"), EdgeMounts, optionalField(volume, "secret"))
+		add("PersistentVolumeClaim", stringField(volume, "persistentVolumeClaim", "claimName"), EdgeMounts, false)
+
+		projected, _ := volume["projected"].(map[string]interface{})
+		sources, _ := projected["sources"].([]interface{})
+		for _, s := range sources {
+			source, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			add("ConfigMap", stringField(source, "configMap", "name"), EdgeMounts, optionalField(source, "configMap"))
+			add("Secret", stringField(source, "secret", "name"), EdgeMounts, optionalField(source, "secret"))
+		}
+	}
+
+	for _, container := range utils.Containers(podSpec) {
+		envFrom, _ := container["envFrom"].([]interface{})
+		for _, e := range envFrom {
+			source, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			add("ConfigMap", stringField(source, "configMapRef", "name"), EdgeEnvFrom, optionalField(source, "configMapRef"))
+			add("Secret", stringField(source, "secretRef", "name"), EdgeEnvFrom, optionalField(source, "secretRef"))
+		}
+
+		env, _ := container["env"].([]interface{})
+		for _, e := range env {
+			variable, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			valueFrom, _ := variable["valueFrom"].(map[string]interface{})
+			add("ConfigMap", stringField(valueFrom, "configMapKeyRef", "name"), EdgeEnvFrom, optionalField(valueFrom, "configMapKeyRef"))
+			add("Secret", stringField(valueFrom, "secretKeyRef", "name"), EdgeEnvFrom, optionalField(valueFrom, "secretKeyRef"))
+		}
+	}
+
+	serviceAccount, _ := podSpec["serviceAccountName"].(string)
+	add("ServiceAccount", serviceAccount, EdgeReference, false)
+
+	return refs
+}
+
+func stringField(parent map[string]interface{}, object, field string) string {
+	child, _ := parent[object].(map[string]interface{})
+	value, _ := child[field].(string)
+	return value
+}
+
+func optionalField(parent map[string]interface{}, object string) bool {
+	child, _ := parent[object].(map[string]interface{})
+	optional, _ := child["optional"].(bool)
+	return optional
+}
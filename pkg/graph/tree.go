@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+type TreeNode struct {
+	Resource      *models.KubernetesResource
+	Children      []*TreeNode
+	MissingOwners []models.OwnerReference
+}
+
+func (t *TreeNode) IsOrphan() bool {
+	return len(t.MissingOwners) > 0
+}
+
+// OwnerForest arranges resources into trees by owner reference. A resource
+// becomes a root when none of its owners are in scope; MissingOwners records
+// owner UIDs that are absent from the whole snapshot, which marks orphans.
+// Owners of a kind the snapshot has none of (a Job, when Jobs were not
+// captured) are not counted as missing.
+func OwnerForest(all []*models.KubernetesResource, scope []*models.KubernetesResource) []*TreeNode {
+	present := make(map[string]bool, len(all))
+	captured := make(map[string]bool)
+	for _, resource := range all {
+		if resource != nil {
+			present[resource.UID()] = true
+			captured[resource.Kind] = true
+		}
+	}
+
+	nodes := make(map[string]*TreeNode, len(scope))
+	var ordered []*TreeNode
+	for _, resource := range scope {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		node := &TreeNode{Resource: resource}
+		if uid := resource.UID(); uid != "" {
+			if _, exists := nodes[uid]; exists {
+				continue
+			}
+			nodes[uid] = node
+		}
+		ordered = append(ordered, node)
+	}
+
+	var roots []*TreeNode
+	for _, node := range ordered {
+		refs, _ := node.Resource.KubernetesResourceMeta.DecodeOwnerReferences()
+		attached := false
+		for _, ref := range refs {
+			if !present[ref.UID] {
+				if !captured[ref.Kind] {
+					continue
+				}
+				node.MissingOwners = append(node.MissingOwners, ref)
+				continue
+			}
+			if parent, ok := nodes[ref.UID]; ok && !attached {
+				parent.Children = append(parent.Children, node)
+				attached = true
+			}
+		}
+		if !attached {
+			roots = append(roots, node)
+		}
+	}
+
+	sortTree(roots)
+	return roots
+}
+
+func sortTree(nodes []*TreeNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].Resource, nodes[j].Resource
+		if a.Namespace() != b.Namespace() {
+			return a.Namespace() < b.Namespace()
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name() < b.Name()
+	})
+	for _, node := range nodes {
+		sortTree(node.Children)
+	}
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func owned(kind, name, uid string, owners ...models.OwnerReference) *models.KubernetesResource {
+	refs := "[]"
+	if len(owners) > 0 {
+		refs = fmt.Sprintf(`[{"kind":%q,"name":%q,"uid":%q}]`, owners[0].Kind, owners[0].Name, owners[0].UID)
+	}
+	return &models.KubernetesResource{
+		Kind: kind,
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             uid,
+			OwnerReferences: refs,
+		},
+	}
+}
+
+func TestOwnerForestOrphans(t *testing.T) {
+	deployment := owned("Deployment", "api", "d1")
+	replicaSet := owned("ReplicaSet", "api-1", "r1", models.OwnerReference{Kind: "Deployment", Name: "api", UID: "d1"})
+	// Owned by a ReplicaSet that has been deleted: an orphan.
+	stale := owned("Pod", "api-0-x", "p1", models.OwnerReference{Kind: "ReplicaSet", Name: "api-0", UID: "r0"})
+	// Owned by a Job, a kind the snapshot does not capture.
+	jobPod := owned("Pod", "migrate-x", "p2", models.OwnerReference{Kind: "Job", Name: "migrate", UID: "j1"})
+	all := []*models.KubernetesResource{deployment, replicaSet, stale, jobPod}
+
+	roots := OwnerForest(all, all)
+	got := map[string]bool{}
+	for _, root := range roots {
+		got[root.Resource.Name()] = root.IsOrphan()
+	}
+	want := map[string]bool{"api": false, "api-0-x": true, "migrate-x": false}
+	if len(got) != len(want) {
+		t.Fatalf("roots = %v, want %v", got, want)
+	}
+	for name, orphan := range want {
+		if got[name] != orphan {
+			t.Errorf("%s orphan = %v, want %v", name, got[name], orphan)
+		}
+	}
+}
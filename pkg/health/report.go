@@ -0,0 +1,375 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const (
+	ReportFormatText     = "text"
+	ReportFormatJSON     = "json"
+	ReportFormatMarkdown = "markdown"
+)
+
+var nodePressureConditions = []string{"MemoryPressure", "DiskPressure", "PIDPressure", "NetworkUnavailable"}
+
+type ReportOptions struct {
+	RestartThreshold int64
+}
+
+type Report struct {
+	ClusterID  string           `json:"cluster_id"`
+	Timestamp  string           `json:"timestamp,omitempty"`
+	Summary    ReportSummary    `json:"summary"`
+	Pods       []PodIssue       `json:"pods"`
+	Containers []ContainerIssue `json:"containers"`
+	Workloads  []WorkloadIssue  `json:"workloads"`
+	Nodes      []NodeIssue      `json:"nodes"`
+}
+
+type ReportSummary struct {
+	Pods              int `json:"pods"`
+	UnhealthyPods     int `json:"unhealthy_pods"`
+	Nodes             int `json:"nodes"`
+	UnhealthyNodes    int `json:"unhealthy_nodes"`
+	Workloads         int `json:"workloads"`
+	DegradedWorkloads int `json:"degraded_workloads"`
+	ContainerIssues   int `json:"container_issues"`
+}
+
+type PodIssue struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ContainerIssue struct {
+	Namespace       string `json:"namespace"`
+	Pod             string `json:"pod"`
+	Container       string `json:"container"`
+	Restarts        int64  `json:"restarts"`
+	WaitingReason   string `json:"waiting_reason,omitempty"`
+	LastTermination string `json:"last_termination,omitempty"`
+}
+
+type WorkloadIssue struct {
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Desired     int64  `json:"desired"`
+	Ready       int64  `json:"ready"`
+	Unavailable int64  `json:"unavailable"`
+}
+
+type NodeIssue struct {
+	Name       string   `json:"name"`
+	Ready      bool     `json:"ready"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+func BuildReport(snap *models.Snapshot, opts ReportOptions) *Report {
+	report := &Report{
+		ClusterID:  snap.ClusterID,
+		Timestamp:  snap.Timestamp,
+		Pods:       []PodIssue{},
+		Containers: []ContainerIssue{},
+		Workloads:  []WorkloadIssue{},
+		Nodes:      []NodeIssue{},
+	}
+
+	for _, resource := range snap.Resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		status, err := resource.Status.Decode()
+		if err != nil {
+			continue
+		}
+
+		switch resource.Kind {
+		case "Pod":
+			report.Summary.Pods++
+			report.addPod(resource, status, opts)
+		case "Node":
+			report.Summary.Nodes++
+			report.addNode(resource, status)
+		case "Deployment", "StatefulSet", "DaemonSet":
+			report.Summary.Workloads++
+			report.addWorkload(resource, status)
+		}
+	}
+
+	report.Summary.UnhealthyPods = len(report.Pods)
+	report.Summary.UnhealthyNodes = len(report.Nodes)
+	report.Summary.DegradedWorkloads = len(report.Workloads)
+	report.Summary.ContainerIssues = len(report.Containers)
+	report.sort()
+	return report
+}
+
+func (r *Report) HasIssues() bool {
+	return len(r.Pods)+len(r.Containers)+len(r.Workloads)+len(r.Nodes) > 0
+}
+
+func (r *Report) addPod(resource *models.KubernetesResource, status map[string]interface{}, opts ReportOptions) {
+	phase, _ := status["phase"].(string)
+	if phase != "Running" && phase != "Succeeded" {
+		reason, _ := status["reason"].(string)
+		message, _ := status["message"].(string)
+		if reason == "" {
+			reason = WaitingReason(status)
+		}
+		r.Pods = append(r.Pods, PodIssue{
+			Namespace: resource.Namespace(),
+			Name:      resource.Name(),
+			Phase:     phase,
+			Reason:    reason,
+			Message:   message,
+		})
+	}
+
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := status[field].([]interface{})
+		for _, item := range statuses {
+			containerStatus, _ := item.(map[string]interface{})
+			name, _ := containerStatus["name"].(string)
+			restarts := Int(containerStatus["restartCount"])
+
+			state, _ := containerStatus["state"].(map[string]interface{})
+			waiting, _ := state["waiting"].(map[string]interface{})
+			waitingReason, _ := waiting["reason"].(string)
+
+			lastState, _ := containerStatus["lastState"].(map[string]interface{})
+			terminated, _ := lastState["terminated"].(map[string]interface{})
+			lastTermination := ""
+			if reason, ok := terminated["reason"].(string); ok {
+				lastTermination = fmt.Sprintf("%s (exit %d)", reason, Int(terminated["exitCode"]))
+			}
+
+			crashLooping := waitingReason == "CrashLoopBackOff"
+			if restarts < opts.RestartThreshold && !crashLooping {
+				continue
+			}
+			r.Containers = append(r.Containers, ContainerIssue{
+				Namespace:       resource.Namespace(),
+				Pod:             resource.Name(),
+				Container:       name,
+				Restarts:        restarts,
+				WaitingReason:   waitingReason,
+				LastTermination: lastTermination,
+			})
+		}
+	}
+}
+
+func (r *Report) addNode(resource *models.KubernetesResource, status map[string]interface{}) {
+	ready := ConditionStatus(status, "Ready") == "True"
+	var pressure []string
+	for _, condition := range nodePressureConditions {
+		if ConditionStatus(status, condition) == "True" {
+			pressure = append(pressure, condition)
+		}
+	}
+
+	if ready && len(pressure) == 0 {
+		return
+	}
+	r.Nodes = append(r.Nodes, NodeIssue{
+		Name:       resource.Name(),
+		Ready:      ready,
+		Conditions: pressure,
+	})
+}
+
+func (r *Report) addWorkload(resource *models.KubernetesResource, status map[string]interface{}) {
+	var desired, ready, unavailable int64
+	if resource.Kind == "DaemonSet" {
+		desired = Int(status["desiredNumberScheduled"])
+		ready = Int(status["numberReady"])
+		unavailable = Int(status["numberUnavailable"])
+	} else {
+		spec, _ := resource.Spec.Decode()
+		desired = Int(spec["replicas"])
+		if _, ok := spec["replicas"]; !ok {
+			desired = 1
+		}
+		ready = Int(status["readyReplicas"])
+		unavailable = Int(status["unavailableReplicas"])
+		if unavailable == 0 && desired > Int(status["availableReplicas"]) {
+			unavailable = desired - Int(status["availableReplicas"])
+		}
+	}
+
+	if unavailable == 0 && ready >= desired {
+		return
+	}
+	r.Workloads = append(r.Workloads, WorkloadIssue{
+		Kind:        resource.Kind,
+		Namespace:   resource.Namespace(),
+		Name:        resource.Name(),
+		Desired:     desired,
+		Ready:       ready,
+		Unavailable: unavailable,
+	})
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Pods, func(i, j int) bool {
+		return r.Pods[i].Namespace+"/"+r.Pods[i].Name < r.Pods[j].Namespace+"/"+r.Pods[j].Name
+	})
+	sort.SliceStable(r.Containers, func(i, j int) bool {
+		return r.Containers[i].Restarts > r.Containers[j].Restarts
+	})
+	sort.SliceStable(r.Workloads, func(i, j int) bool {
+		return r.Workloads[i].Namespace+"/"+r.Workloads[i].Name < r.Workloads[j].Namespace+"/"+r.Workloads[j].Name
+	})
+	sort.SliceStable(r.Nodes, func(i, j int) bool {
+		return r.Nodes[i].Name < r.Nodes[j].Name
+	})
+}
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportFormatText:
+		return r.writeText(w)
+	case ReportFormatMarkdown:
+		return r.writeMarkdown(w)
+	case ReportFormatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	default:
+		return fmt.Errorf("unsupported report format %q (use text, json or markdown)", format)
+	}
+}
+
+func (r *Report) writeText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cluster health report (cluster %s", r.ClusterID)
+	if r.Timestamp != "" {
+		fmt.Fprintf(&b, ", captured %s", r.Timestamp)
+	}
+	b.WriteString(")\n")
+	fmt.Fprintf(&b, "  Pods: %d unhealthy of %d\n", r.Summary.UnhealthyPods, r.Summary.Pods)
+	fmt.Fprintf(&b, "  Workloads: %d degraded of %d\n", r.Summary.DegradedWorkloads, r.Summary.Workloads)
+	fmt.Fprintf(&b, "  Nodes: %d unhealthy of %d\n", r.Summary.UnhealthyNodes, r.Summary.Nodes)
+	fmt.Fprintf(&b, "  Containers with restarts or crash loops: %d\n", r.Summary.ContainerIssues)
+
+	if !r.HasIssues() {
+		b.WriteString("\nNo issues found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(r.Nodes) > 0 {
+		b.WriteString("\nNodes:\n")
+		for _, node := range r.Nodes {
+			fmt.Fprintf(&b, "  - %s: %s\n", node.Name, nodeDescription(node))
+		}
+	}
+	if len(r.Workloads) > 0 {
+		b.WriteString("\nWorkloads with unavailable replicas:\n")
+		for _, wl := range r.Workloads {
+			fmt.Fprintf(&b, "  - %s %s/%s: %d/%d ready, %d unavailable\n",
+				wl.Kind, wl.Namespace, wl.Name, wl.Ready, wl.Desired, wl.Unavailable)
+		}
+	}
+	if len(r.Pods) > 0 {
+		b.WriteString("\nPods not Running/Succeeded:\n")
+		for _, pod := range r.Pods {
+			fmt.Fprintf(&b, "  - %s/%s: %s", pod.Namespace, pod.Name, valueOr(pod.Phase, "Unknown"))
+			if pod.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", pod.Reason)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(r.Containers) > 0 {
+		b.WriteString("\nContainers:\n")
+		for _, c := range r.Containers {
+			fmt.Fprintf(&b, "  - %s/%s [%s]: %d restarts", c.Namespace, c.Pod, c.Container, c.Restarts)
+			if c.WaitingReason != "" {
+				fmt.Fprintf(&b, ", %s", c.WaitingReason)
+			}
+			if c.LastTermination != "" {
+				fmt.Fprintf(&b, ", last terminated: %s", c.LastTermination)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Cluster health report\n\n")
+	fmt.Fprintf(&b, "- **Cluster:** `%s`\n", r.ClusterID)
+	if r.Timestamp != "" {
+		fmt.Fprintf(&b, "- **Captured:** %s\n", r.Timestamp)
+	}
+	fmt.Fprintf(&b, "- **Pods:** %d unhealthy of %d\n", r.Summary.UnhealthyPods, r.Summary.Pods)
+	fmt.Fprintf(&b, "- **Workloads:** %d degraded of %d\n", r.Summary.DegradedWorkloads, r.Summary.Workloads)
+	fmt.Fprintf(&b, "- **Nodes:** %d unhealthy of %d\n", r.Summary.UnhealthyNodes, r.Summary.Nodes)
+
+	if !r.HasIssues() {
+		b.WriteString("\nNo issues found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(r.Nodes) > 0 {
+		b.WriteString("\n## Nodes\n\n| Node | Status |\n| --- | --- |\n")
+		for _, node := range r.Nodes {
+			fmt.Fprintf(&b, "| %s | %s |\n", node.Name, nodeDescription(node))
+		}
+	}
+	if len(r.Workloads) > 0 {
+		b.WriteString("\n## Workloads with unavailable replicas\n\n| Kind | Namespace | Name | Ready | Unavailable |\n| --- | --- | --- | --- | --- |\n")
+		for _, wl := range r.Workloads {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d/%d | %d |\n", wl.Kind, wl.Namespace, wl.Name, wl.Ready, wl.Desired, wl.Unavailable)
+		}
+	}
+	if len(r.Pods) > 0 {
+		b.WriteString("\n## Pods not Running/Succeeded\n\n| Namespace | Pod | Phase | Reason |\n| --- | --- | --- | --- |\n")
+		for _, pod := range r.Pods {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", pod.Namespace, pod.Name, valueOr(pod.Phase, "Unknown"), pod.Reason)
+		}
+	}
+	if len(r.Containers) > 0 {
+		b.WriteString("\n## Containers\n\n| Namespace | Pod | Container | Restarts | Waiting | Last termination |\n| --- | --- | --- | --- | --- | --- |\n")
+		for _, c := range r.Containers {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %s | %s |\n", c.Namespace, c.Pod, c.Container, c.Restarts, c.WaitingReason, c.LastTermination)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func nodeDescription(node NodeIssue) string {
+	parts := []string{}
+	if !node.Ready {
+		parts = append(parts, "NotReady")
+	}
+	parts = append(parts, node.Conditions...)
+	return strings.Join(parts, ", ")
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const (
+	StateReady    = "Ready"
+	StateNotReady = "NotReady"
+	StateUnknown  = "Unknown"
+)
+
+type Status struct {
+	State  string
+	Detail string
+}
+
+func (s Status) Glyph() string {
+	switch s.State {
+	case StateReady:
+		return "✓"
+	case StateNotReady:
+		return "✗"
+	default:
+		return "?"
+	}
+}
+
+// Evaluate derives a readiness state from a resource's decoded status for the
+// kinds whose status has a well-known shape. Anything else is Unknown.
+func Evaluate(resource *models.KubernetesResource) Status {
+	status, err := resource.Status.Decode()
+	if err != nil || len(status) == 0 {
+		return Status{State: StateUnknown}
+	}
+
+	switch resource.Kind {
+	case "Pod":
+		return podStatus(status)
+	case "Node":
+		if ConditionStatus(status, "Ready") == "True" {
+			return Status{State: StateReady, Detail: "Ready"}
+		}
+		return Status{State: StateNotReady, Detail: "NotReady"}
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		spec, _ := resource.Spec.Decode()
+		desired := Int(spec["replicas"])
+		if _, ok := spec["replicas"]; !ok {
+			desired = Int(status["replicas"])
+		}
+		return replicaStatus(Int(status["readyReplicas"]), desired)
+	case "DaemonSet":
+		return replicaStatus(Int(status["numberReady"]), Int(status["desiredNumberScheduled"]))
+	case "Job":
+		if ConditionStatus(status, "Failed") == "True" {
+			return Status{State: StateNotReady, Detail: "Failed"}
+		}
+		if ConditionStatus(status, "Complete") == "True" {
+			return Status{State: StateReady, Detail: "Complete"}
+		}
+		return Status{State: StateUnknown, Detail: "Running"}
+	case "Namespace", "PersistentVolumeClaim", "PersistentVolume":
+		phase, _ := status["phase"].(string)
+		if phase == "Active" || phase == "Bound" {
+			return Status{State: StateReady, Detail: phase}
+		}
+		return Status{State: StateNotReady, Detail: phase}
+	}
+
+	return Status{State: StateUnknown}
+}
+
+func podStatus(status map[string]interface{}) Status {
+	phase, _ := status["phase"].(string)
+	if reason := WaitingReason(status); reason != "" {
+		return Status{State: StateNotReady, Detail: reason}
+	}
+
+	switch phase {
+	case "Succeeded":
+		return Status{State: StateReady, Detail: phase}
+	case "Running":
+		if ConditionStatus(status, "Ready") == "True" {
+			return Status{State: StateReady, Detail: phase}
+		}
+		return Status{State: StateNotReady, Detail: "Running, not ready"}
+	case "":
+		return Status{State: StateUnknown}
+	default:
+		return Status{State: StateNotReady, Detail: phase}
+	}
+}
+
+func replicaStatus(ready, desired int64) Status {
+	detail := fmt.Sprintf("%d/%d", ready, desired)
+	if ready >= desired {
+		return Status{State: StateReady, Detail: detail}
+	}
+	return Status{State: StateNotReady, Detail: detail}
+}
+
+// WaitingReason returns the first container waiting reason, such as
+// CrashLoopBackOff or ImagePullBackOff, from a decoded pod status.
+func WaitingReason(status map[string]interface{}) string {
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := status[field].([]interface{})
+		for _, item := range statuses {
+			containerStatus, _ := item.(map[string]interface{})
+			state, _ := containerStatus["state"].(map[string]interface{})
+			waiting, _ := state["waiting"].(map[string]interface{})
+			if reason, ok := waiting["reason"].(string); ok && reason != "" && reason != "ContainerCreating" && reason != "PodInitializing" {
+				return reason
+			}
+		}
+	}
+	return ""
+}
+
+func ConditionStatus(status map[string]interface{}, conditionType string) string {
+	conditions, _ := status["conditions"].([]interface{})
+	for _, item := range conditions {
+		condition, _ := item.(map[string]interface{})
+		if condition["type"] == conditionType {
+			value, _ := condition["status"].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+// Int reads a JSON number decoded into interface{} (float64 or json.Number).
+func Int(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case interface{ Int64() (int64, error) }:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package inventory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+var workloadKinds = map[string]bool{
+	"Pod":         true,
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+	"Job":         true,
+	"CronJob":     true,
+}
+
+type Image struct {
+	Image      string        `json:"image"`
+	Registry   string        `json:"registry"`
+	Repository string        `json:"repository"`
+	Tag        string        `json:"tag,omitempty"`
+	Digests    []string      `json:"digests,omitempty"`
+	Workloads  []WorkloadRef `json:"workloads"`
+}
+
+type WorkloadRef struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace,omitempty"`
+	Name          string `json:"name"`
+	Container     string `json:"container"`
+	ContainerType string `json:"container_type"`
+}
+
+func (w WorkloadRef) String() string {
+	if w.Namespace == "" {
+		return w.Kind + "/" + w.Name + ":" + w.Container
+	}
+	return w.Kind + "/" + w.Namespace + "/" + w.Name + ":" + w.Container
+}
+
+// ownedByWorkload reports whether a controller further up the chain already
+// lists the same containers; static Pods are owned by their Node and still
+// count as workloads of their own.
+func ownedByWorkload(resource *models.KubernetesResource) bool {
+	refs, err := resource.KubernetesResourceMeta.DecodeOwnerReferences()
+	if err != nil {
+		return false
+	}
+	for _, ref := range refs {
+		if ref.IsController() && workloadKinds[ref.Kind] {
+			return true
+		}
+	}
+	return false
+}
+
+// Images walks every pod template in the snapshot, including init and
+// ephemeral containers, and groups containers by image. Digests come from
+// the imageID in Pod container statuses. Pods created by a controller only
+// contribute digests; their controller is listed as the workload instead.
+func Images(resources []*models.KubernetesResource) []*Image {
+	images := make(map[string]*Image)
+	digests := make(map[string]map[string]bool)
+
+	get := func(image string) *Image {
+		if existing, ok := images[image]; ok {
+			return existing
+		}
+		ref := utils.ParseImageReference(image)
+		entry := &Image{
+			Image:      image,
+			Registry:   ref.Registry,
+			Repository: ref.Repository,
+			Tag:        ref.Tag,
+			Workloads:  []WorkloadRef{},
+		}
+		if ref.Digest != "" {
+			digests[image] = map[string]bool{ref.Digest: true}
+		}
+		images[image] = entry
+		return entry
+	}
+
+	for _, resource := range resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil || !workloadKinds[resource.Kind] {
+			continue
+		}
+		spec, err := resource.Spec.Decode()
+		if err != nil {
+			continue
+		}
+		podSpec := utils.PodSpec(resource.Kind, spec)
+		if podSpec == nil {
+			continue
+		}
+
+		owned := ownedByWorkload(resource)
+		containerImages := make(map[string]string)
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			for _, container := range utils.Containers(podSpec, field) {
+				image, _ := container["image"].(string)
+				name, _ := container["name"].(string)
+				if image == "" {
+					continue
+				}
+				containerImages[name] = image
+				entry := get(image)
+				if owned {
+					continue
+				}
+				entry.Workloads = append(entry.Workloads, WorkloadRef{
+					Kind:          resource.Kind,
+					Namespace:     resource.Namespace(),
+					Name:          resource.Name(),
+					Container:     name,
+					ContainerType: strings.TrimSuffix(field, "s"),
+				})
+			}
+		}
+
+		if resource.Kind == "Pod" {
+			collectDigests(resource, containerImages, digests)
+		}
+	}
+
+	var result []*Image
+	for image, entry := range images {
+		for digest := range digests[image] {
+			entry.Digests = append(entry.Digests, digest)
+		}
+		sort.Strings(entry.Digests)
+		sort.SliceStable(entry.Workloads, func(i, j int) bool {
+			return entry.Workloads[i].String() < entry.Workloads[j].String()
+		})
+		result = append(result, entry)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Image < result[j].Image
+	})
+	return result
+}
+
+func collectDigests(pod *models.KubernetesResource, containerImages map[string]string, digests map[string]map[string]bool) {
+	status, err := pod.Status.Decode()
+	if err != nil {
+		return
+	}
+	for _, field := range []string{"initContainerStatuses", "containerStatuses", "ephemeralContainerStatuses"} {
+		statuses, _ := status[field].([]interface{})
+		for _, item := range statuses {
+			containerStatus, _ := item.(map[string]interface{})
+			name, _ := containerStatus["name"].(string)
+			imageID, _ := containerStatus["imageID"].(string)
+			image, ok := containerImages[name]
+			if !ok {
+				continue
+			}
+			idx := strings.LastIndex(imageID, "@")
+			if idx < 0 {
+				continue
+			}
+			if digests[image] == nil {
+				digests[image] = make(map[string]bool)
+			}
+			digests[image][imageID[idx+1:]] = true
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+const (
+	FormatText      = "text"
+	FormatCSV       = "csv"
+	FormatJSON      = "json"
+	FormatCycloneDX = "cyclonedx"
+)
+
+type WriteOptions struct {
+	ClusterID string
+	Timestamp string
+}
+
+func Write(w io.Writer, format string, images []*Image, opts WriteOptions) error {
+	switch format {
+	case FormatText:
+		return writeText(w, images)
+	case FormatCSV:
+		return writeCSV(w, images)
+	case FormatJSON:
+		data, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal image inventory: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatCycloneDX:
+		return writeCycloneDX(w, images, opts)
+	default:
+		return fmt.Errorf("unsupported image format %q (use text, csv, json or cyclonedx)", format)
+	}
+}
+
+func writeText(w io.Writer, images []*Image) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REGISTRY\tREPOSITORY\tTAG\tDIGEST\tWORKLOADS")
+	for _, image := range images {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", image.Registry, image.Repository, dash(image.Tag),
+			dash(shortDigest(strings.Join(image.Digests, ","))), len(image.Workloads))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, images []*Image) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"image", "registry", "repository", "tag", "digests", "workloads"}); err != nil {
+		return err
+	}
+	for _, image := range images {
+		var workloads []string
+		for _, workload := range image.Workloads {
+			workloads = append(workloads, workload.String())
+		}
+		record := []string{
+			image.Image,
+			image.Registry,
+			image.Repository,
+			image.Tag,
+			strings.Join(image.Digests, ";"),
+			strings.Join(workloads, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type cycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp"`
+	Tools     cycloneDXTools      `json:"tools"`
+	Component cycloneDXComponent  `json:"component"`
+	Props     []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func writeCycloneDX(w io.Writer, images []*Image, opts WriteOptions) error {
+	timestamp := opts.Timestamp
+	if timestamp == "" {
+		timestamp = time.Now().Format(time.RFC3339)
+	}
+
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + utils.NameUUID("cyclonedx", opts.ClusterID, timestamp),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: timestamp,
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{{
+				Type:    "application",
+				Name:    "kubectl-meshsync_snapshot",
+				Version: "0.1.0",
+			}}},
+			Component: cycloneDXComponent{
+				Type: "platform",
+				Name: "kubernetes-cluster",
+				Properties: []cycloneDXProperty{
+					{Name: "meshsync:cluster_id", Value: opts.ClusterID},
+				},
+			},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for _, image := range images {
+		name := image.Repository[strings.LastIndex(image.Repository, "/")+1:]
+		repositoryURL := image.Registry + "/" + image.Repository
+
+		versions := image.Digests
+		if len(versions) == 0 {
+			versions = []string{""}
+		}
+		for _, digest := range versions {
+			component := cycloneDXComponent{
+				BOMRef:  utils.NameUUID(image.Image, digest),
+				Type:    "container",
+				Name:    repositoryURL,
+				Version: image.Tag,
+				PURL:    containerPURL(name, digest, repositoryURL, image.Tag),
+			}
+			if strings.HasPrefix(digest, "sha256:") {
+				component.Hashes = []cycloneDXHash{{Alg: "SHA-256", Content: strings.TrimPrefix(digest, "sha256:")}}
+			}
+			for _, workload := range image.Workloads {
+				component.Properties = append(component.Properties, cycloneDXProperty{
+					Name:  "meshsync:workload",
+					Value: workload.String(),
+				})
+			}
+			bom.Components = append(bom.Components, component)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bom); err != nil {
+		return fmt.Errorf("failed to marshal CycloneDX document: %w", err)
+	}
+	return nil
+}
+
+// containerPURL builds a pkg:oci package URL; the version component of an
+// OCI purl is the manifest digest, so it is left out when none is known.
+func containerPURL(name, digest, repositoryURL, tag string) string {
+	purl := "pkg:oci/" + url.PathEscape(name)
+	if digest != "" {
+		purl += "@" + url.QueryEscape(digest)
+	}
+	query := url.Values{}
+	query.Set("repository_url", repositoryURL)
+	if tag != "" {
+		query.Set("tag", tag)
+	}
+	return purl + "?" + query.Encode()
+}
+
+func shortDigest(digest string) string {
+	if strings.HasPrefix(digest, "sha256:") && len(digest) > 19 {
+		return digest[:19]
+	}
+	return digest
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,261 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+var podTemplateKinds = map[string]bool{
+	"Pod":         true,
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+	"Job":         true,
+	"CronJob":     true,
+}
+
+type builtinRule struct {
+	id          string
+	description string
+	severity    string
+	check       func(ctx *Context, resource *models.KubernetesResource) []string
+}
+
+func (r *builtinRule) ID() string          { return r.id }
+func (r *builtinRule) Description() string { return r.description }
+func (r *builtinRule) Severity() string    { return r.severity }
+
+func (r *builtinRule) Check(ctx *Context, resource *models.KubernetesResource) ([]string, error) {
+	return r.check(ctx, resource), nil
+}
+
+func BuiltinRules() []Rule {
+	return []Rule{
+		&builtinRule{
+			id:          "container-resources",
+			description: "Containers should set CPU and memory requests and limits",
+			severity:    SeverityWarning,
+			check:       forEachContainer(false, checkResources),
+		},
+		&builtinRule{
+			id:          "image-latest-tag",
+			description: "Container images should be pinned to a tag other than latest or to a digest",
+			severity:    SeverityWarning,
+			check:       forEachContainer(true, checkLatestTag),
+		},
+		&builtinRule{
+			id:          "privileged-container",
+			description: "Containers should not run privileged",
+			severity:    SeverityError,
+			check:       forEachContainer(true, checkPrivileged),
+		},
+		&builtinRule{
+			id:          "host-path-volume",
+			description: "Pods should not mount hostPath volumes",
+			severity:    SeverityWarning,
+			check:       checkHostPath,
+		},
+		&builtinRule{
+			id:          "missing-probes",
+			description: "Long-running containers should define liveness and readiness probes",
+			severity:    SeverityWarning,
+			check:       checkProbes,
+		},
+		serviceSelectorRule(),
+		&builtinRule{
+			id:          "single-replica-deployment",
+			description: "Deployments with a single replica have no redundancy",
+			severity:    SeverityNote,
+			check:       checkSingleReplica,
+		},
+	}
+}
+
+func serviceSelectorRule() Rule {
+	return &builtinRule{
+		id:          "service-no-matching-pods",
+		description: "Service selectors should match at least one Pod",
+		severity:    SeverityWarning,
+		check:       checkServiceSelector,
+	}
+}
+
+// podSpecOf returns the pod spec to lint for a resource. Pods created by a
+// controller are skipped so that findings are reported once, on the template.
+func podSpecOf(resource *models.KubernetesResource) map[string]interface{} {
+	if !podTemplateKinds[resource.Kind] {
+		return nil
+	}
+	if resource.Kind == "Pod" && resource.IsControllerOwned() {
+		return nil
+	}
+	if resource.Kind == "ReplicaSet" && resource.IsControllerOwned() {
+		return nil
+	}
+	spec, err := resource.Spec.Decode()
+	if err != nil {
+		return nil
+	}
+	return utils.PodSpec(resource.Kind, spec)
+}
+
+func forEachContainer(includeInit bool, check func(container map[string]interface{}) string) func(*Context, *models.KubernetesResource) []string {
+	return func(_ *Context, resource *models.KubernetesResource) []string {
+		podSpec := podSpecOf(resource)
+		if podSpec == nil {
+			return nil
+		}
+		fields := []string{"containers"}
+		if includeInit {
+			fields = append(fields, "initContainers", "ephemeralContainers")
+		}
+
+		var messages []string
+		for _, container := range utils.Containers(podSpec, fields...) {
+			if message := check(container); message != "" {
+				name, _ := container["name"].(string)
+				messages = append(messages, fmt.Sprintf("container %q %s", name, message))
+			}
+		}
+		return messages
+	}
+}
+
+func checkResources(container map[string]interface{}) string {
+	resources, _ := container["resources"].(map[string]interface{})
+	requests, _ := resources["requests"].(map[string]interface{})
+	limits, _ := resources["limits"].(map[string]interface{})
+
+	var missing []string
+	for _, resource := range []string{"cpu", "memory"} {
+		if _, ok := requests[resource]; !ok {
+			missing = append(missing, resource+" request")
+		}
+		if _, ok := limits[resource]; !ok {
+			missing = append(missing, resource+" limit")
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("has no %s", joinList(missing))
+}
+
+func checkLatestTag(container map[string]interface{}) string {
+	image, _ := container["image"].(string)
+	if image == "" {
+		return ""
+	}
+	ref := utils.ParseImageReference(image)
+	if ref.Digest == "" && ref.Tag == "latest" {
+		return fmt.Sprintf("uses image %q with the latest tag", image)
+	}
+	return ""
+}
+
+func checkPrivileged(container map[string]interface{}) string {
+	securityContext, _ := container["securityContext"].(map[string]interface{})
+	if privileged, _ := securityContext["privileged"].(bool); privileged {
+		return "runs privileged"
+	}
+	return ""
+}
+
+func checkHostPath(_ *Context, resource *models.KubernetesResource) []string {
+	podSpec := podSpecOf(resource)
+	if podSpec == nil {
+		return nil
+	}
+
+	var messages []string
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, item := range volumes {
+		volume, _ := item.(map[string]interface{})
+		hostPath, ok := volume["hostPath"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := hostPath["path"].(string)
+		messages = append(messages, fmt.Sprintf("volume %q mounts host path %s", volume["name"], path))
+	}
+	return messages
+}
+
+func checkProbes(_ *Context, resource *models.KubernetesResource) []string {
+	if resource.Kind == "Job" || resource.Kind == "CronJob" {
+		return nil
+	}
+	podSpec := podSpecOf(resource)
+	if podSpec == nil {
+		return nil
+	}
+
+	var messages []string
+	for _, container := range utils.Containers(podSpec, "containers") {
+		var missing []string
+		if _, ok := container["livenessProbe"]; !ok {
+			missing = append(missing, "liveness")
+		}
+		if _, ok := container["readinessProbe"]; !ok {
+			missing = append(missing, "readiness")
+		}
+		if len(missing) > 0 {
+			name, _ := container["name"].(string)
+			messages = append(messages, fmt.Sprintf("container %q has no %s probe", name, joinList(missing)))
+		}
+	}
+	return messages
+}
+
+func checkServiceSelector(ctx *Context, resource *models.KubernetesResource) []string {
+	if resource.Kind != "Service" || !ctx.Captured("Pod") {
+		return nil
+	}
+	spec, err := resource.Spec.Decode()
+	if err != nil {
+		return nil
+	}
+	selector := utils.StringMap(spec["selector"])
+	if len(selector) == 0 {
+		return nil
+	}
+
+	for _, pod := range ctx.Resources {
+		if pod != nil && pod.Kind == "Pod" && pod.Namespace() == resource.Namespace() &&
+			utils.MatchesSelector(selector, pod.KubernetesResourceMeta.LabelMap()) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("selector %s matches no Pods", utils.FormatSelector(selector))}
+}
+
+func checkSingleReplica(_ *Context, resource *models.KubernetesResource) []string {
+	if resource.Kind != "Deployment" {
+		return nil
+	}
+	spec, err := resource.Spec.Decode()
+	if err != nil {
+		return nil
+	}
+	if replicas, ok := spec["replicas"].(float64); ok && replicas == 1 {
+		return []string{"runs a single replica"}
+	}
+	return nil
+}
+
+func joinList(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	result := items[0]
+	for _, item := range items[1 : len(items)-1] {
+		result += ", " + item
+	}
+	return result + " or " + items[len(items)-1]
+}
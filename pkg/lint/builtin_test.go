@@ -0,0 +1,45 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestServiceSelectorRule(t *testing.T) {
+	service := &models.KubernetesResource{
+		APIVersion:             "v1",
+		Kind:                   "Service",
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: "web", Namespace: "default"},
+		Spec:                   &models.KubernetesResourceSpec{Attribute: `{"selector":{"app":"web"}}`},
+	}
+	pod := func(app string) *models.KubernetesResource {
+		return &models.KubernetesResource{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+				Name:      app + "-0",
+				Namespace: "default",
+				Labels:    []*models.KubernetesKeyValue{{Key: "app", Value: app}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		resources []*models.KubernetesResource
+		want      int
+	}{
+		{name: "matching pod", resources: []*models.KubernetesResource{service, pod("web")}, want: 0},
+		{name: "no matching pod", resources: []*models.KubernetesResource{service, pod("db")}, want: 1},
+		{name: "pods not captured", resources: []*models.KubernetesResource{service}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := NewEngine(serviceSelectorRule()).Run(tt.resources)
+			if len(findings) != tt.want {
+				t.Errorf("findings = %+v, want %d", findings, tt.want)
+			}
+		})
+	}
+}
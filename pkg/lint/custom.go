@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"gopkg.in/yaml.v3"
+)
+
+type customRuleFile struct {
+	Rules []customRuleSpec `yaml:"rules"`
+}
+
+type customRuleSpec struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description"`
+	Severity    string   `yaml:"severity"`
+	Kinds       []string `yaml:"kinds"`
+	Expression  string   `yaml:"expression"`
+	Message     string   `yaml:"message"`
+}
+
+// celRule flags a resource when its expression evaluates to true. The
+// expression sees the resource as `resource`, with decoded spec and status.
+type celRule struct {
+	spec    customRuleSpec
+	kinds   map[string]bool
+	program cel.Program
+	// fieldAccesses holds the IDs of the expression's field selections and
+	// index operations.
+	fieldAccesses map[int64]bool
+}
+
+func (r *celRule) ID() string          { return r.spec.ID }
+func (r *celRule) Description() string { return r.spec.Description }
+func (r *celRule) Severity() string    { return r.spec.Severity }
+
+func (r *celRule) Check(_ *Context, resource *models.KubernetesResource) ([]string, error) {
+	if len(r.kinds) > 0 && !r.kinds[resource.Kind] {
+		return nil, nil
+	}
+
+	out, _, err := r.program.Eval(map[string]interface{}{"resource": resource.Object()})
+	if err != nil {
+		// Missing fields are no match, so rules do not need a has() guard
+		// for every optional field. cel-go labels an error with the node
+		// that raised it; one raised by a field access means the field is
+		// absent. Anything else, such as comparing mismatched types, is a
+		// mistake in the rule.
+		var evalErr *types.Err
+		if errors.As(err, &evalErr) && r.fieldAccesses[evalErr.NodeID()] {
+			return nil, nil
+		}
+		return nil, err
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return nil, fmt.Errorf("expression returned %v, not a bool", out.Value())
+	}
+	if matched {
+		return []string{r.spec.Message}, nil
+	}
+	return nil, nil
+}
+
+// LoadRules reads custom rules from a YAML file of CEL expressions, or a
+// single rule from a .rego policy.
+func LoadRules(path string) ([]Rule, error) {
+	if strings.EqualFold(filepath.Ext(path), ".rego") {
+		rule, err := loadRegoRule(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Rule{rule}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var file customRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+
+	env, err := cel.NewEnv(cel.Variable("resource", cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	var rules []Rule
+	for i, spec := range file.Rules {
+		if spec.ID == "" || spec.Expression == "" {
+			return nil, fmt.Errorf("%s: rule %d needs an id and an expression", path, i+1)
+		}
+		if spec.Severity == "" {
+			spec.Severity = SeverityWarning
+		}
+		if !ValidSeverity(spec.Severity) {
+			return nil, fmt.Errorf("%s: rule %s has invalid severity %q", path, spec.ID, spec.Severity)
+		}
+		if spec.Message == "" {
+			spec.Message = spec.Description
+		}
+		if spec.Message == "" {
+			spec.Message = fmt.Sprintf("matches rule %s", spec.ID)
+		}
+
+		ast, issues := env.Compile(spec.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("%s: rule %s: %w", path, spec.ID, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("%s: rule %s: expression must evaluate to a bool", path, spec.ID)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rule %s: %w", path, spec.ID, err)
+		}
+
+		kinds := make(map[string]bool, len(spec.Kinds))
+		for _, kind := range spec.Kinds {
+			kinds[kind] = true
+		}
+		rules = append(rules, &celRule{spec: spec, kinds: kinds, program: program, fieldAccesses: fieldAccesses(ast)})
+	}
+	return rules, nil
+}
+
+func fieldAccesses(ast *cel.Ast) map[int64]bool {
+	ids := make(map[int64]bool)
+	celast.PreOrderVisit(ast.NativeRep().Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		switch e.Kind() {
+		case celast.SelectKind:
+			ids[e.ID()] = true
+		case celast.CallKind:
+			if e.AsCall().FunctionName() == operators.Index {
+				ids[e.ID()] = true
+			}
+		}
+	}))
+	return ids
+}
@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestCustomRules(t *testing.T) {
+	deployment := &models.KubernetesResource{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+			Name:      "api",
+			Namespace: "default",
+			Labels:    []*models.KubernetesKeyValue{{Key: "app", Value: "api"}},
+		},
+		Spec: &models.KubernetesResourceSpec{Attribute: `{"replicas":1}`},
+	}
+
+	tests := []struct {
+		name         string
+		expression   string
+		wantSeverity string
+		wantMessage  string
+	}{
+		{name: "match", expression: `resource.spec.replicas < 2`, wantSeverity: SeverityWarning, wantMessage: "flagged"},
+		{name: "no match", expression: `resource.spec.replicas > 2`},
+		{name: "missing label", expression: `!("team" in resource.metadata.labels)`, wantSeverity: SeverityWarning, wantMessage: "flagged"},
+		{name: "missing field is no match", expression: `resource.spec.strategy.type == "Recreate"`},
+		{name: "missing map key is no match", expression: `resource.metadata.labels["team"] == "payments"`},
+		{name: "missing list is no match", expression: `resource.spec.template.spec.containers[0].name == "api"`},
+		{name: "unknown function is reported", expression: `resource.spec.replicas.size() > 0`, wantSeverity: SeverityError, wantMessage: "rule failed to evaluate"},
+		{name: "type error is reported", expression: `resource.spec.replicas == "one" || resource.spec.replicas > "one"`, wantSeverity: SeverityError, wantMessage: "rule failed to evaluate"},
+		{name: "non-bool result is reported", expression: `resource.spec.replicas`, wantSeverity: SeverityError, wantMessage: "not a bool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			content := "rules:\n  - id: custom\n    expression: '" + tt.expression + "'\n    message: flagged\n"
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := LoadRules(path)
+			if err != nil {
+				t.Fatalf("LoadRules: %v", err)
+			}
+			findings := NewEngine(rules...).Run([]*models.KubernetesResource{deployment})
+			if tt.wantSeverity == "" {
+				if len(findings) != 0 {
+					t.Errorf("findings = %+v, want none", findings)
+				}
+				return
+			}
+			if len(findings) != 1 {
+				t.Fatalf("findings = %+v, want one", findings)
+			}
+			if findings[0].Severity != tt.wantSeverity || !strings.Contains(findings[0].Message, tt.wantMessage) {
+				t.Errorf("finding = %+v, want %s containing %q", findings[0], tt.wantSeverity, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRegoRules(t *testing.T) {
+	deployment := &models.KubernetesResource{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+			Name:      "api",
+			Namespace: "default",
+			Labels:    []*models.KubernetesKeyValue{{Key: "app", Value: "api"}},
+		},
+		Spec: &models.KubernetesResourceSpec{Attribute: `{"replicas":1}`},
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		wantErr      bool
+		wantSeverity string
+		wantMessages []string
+	}{
+		{
+			name: "match",
+			policy: `package replicas
+deny contains msg if {
+	input.kind == "Deployment"
+	input.spec.replicas < 2
+	msg := sprintf("%s runs one replica", [input.metadata.name])
+}`,
+			wantSeverity: SeverityWarning,
+			wantMessages: []string{"api runs one replica"},
+		},
+		{
+			name: "missing field is no match",
+			policy: `package strategy
+deny contains "recreate" if input.spec.strategy.type == "Recreate"`,
+		},
+		{
+			name: "severity and conftest messages",
+			policy: `# METADATA
+# title: Team label
+# custom:
+#   severity: error
+package labels.team
+deny contains {"msg": "no team label"} if not input.metadata.labels.team`,
+			wantSeverity: SeverityError,
+			wantMessages: []string{"no team label"},
+		},
+		{
+			name: "runtime error is reported",
+			policy: `package broken
+deny contains msg if msg := input.metadata.name / 2`,
+			wantSeverity: SeverityError,
+			wantMessages: []string{"rule failed to evaluate"},
+		},
+		{name: "no deny rule", policy: "package empty\nallow := true", wantErr: true},
+		{name: "syntax error", policy: "package broken\ndeny[msg] {", wantErr: true},
+		{name: "invalid severity", policy: "# METADATA\n# custom:\n#   severity: fatal\npackage x\ndeny contains \"x\" if true", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.rego")
+			if err := os.WriteFile(path, []byte(tt.policy), 0644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := LoadRules(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadRules error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			findings := NewEngine(rules...).Run([]*models.KubernetesResource{deployment})
+			if len(findings) != len(tt.wantMessages) {
+				t.Fatalf("findings = %+v, want %v", findings, tt.wantMessages)
+			}
+			for i, finding := range findings {
+				if finding.Severity != tt.wantSeverity || !strings.Contains(finding.Message, tt.wantMessages[i]) {
+					t.Errorf("finding = %+v, want %s containing %q", finding, tt.wantSeverity, tt.wantMessages[i])
+				}
+			}
+		})
+	}
+}
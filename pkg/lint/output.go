@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration map[string]string `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func Write(w io.Writer, format string, findings []Finding, rules []Rule, snapshotPath string) error {
+	switch format {
+	case FormatText:
+		return writeText(w, findings)
+	case FormatJSON:
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal findings: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatSARIF:
+		return writeSARIF(w, findings, rules, snapshotPath)
+	default:
+		return fmt.Errorf("unsupported lint format %q (use text, json or sarif)", format)
+	}
+}
+
+func writeText(w io.Writer, findings []Finding) error {
+	var b strings.Builder
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[f.Severity]++
+		location := f.Kind + "/" + f.Name
+		if f.Namespace != "" {
+			location = f.Kind + "/" + f.Namespace + "/" + f.Name
+		}
+		fmt.Fprintf(&b, "%-7s %-26s %s: %s\n", strings.ToUpper(f.Severity), f.RuleID, location, f.Message)
+	}
+	fmt.Fprintf(&b, "\n%d error(s), %d warning(s), %d note(s)\n",
+		counts[SeverityError], counts[SeverityWarning], counts[SeverityNote])
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSARIF(w io.Writer, findings []Finding, rules []Rule, snapshotPath string) error {
+	driver := sarifDriver{
+		Name:           "kubectl-meshsync_snapshot",
+		Version:        "0.1.0",
+		InformationURI: "https://github.com/fyzanshaik/kubectl-meshsync-snapshot",
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID(),
+			ShortDescription:     sarifMessage{Text: rule.Description()},
+			DefaultConfiguration: map[string]string{"level": rule.Severity()},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, f := range findings {
+		name := f.Kind + "/" + f.Name
+		if f.Namespace != "" {
+			name = f.Namespace + "/" + name
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  f.RuleID,
+			Level:   f.Severity,
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", name, f.Message)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: snapshotPath}},
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: name, Kind: "resource"}},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+// ReferenceRules find references to resources that are not in the snapshot.
+// References to kinds the snapshot never captured are not reported.
+func ReferenceRules() []Rule {
+	return []Rule{
+		serviceSelectorRule(),
+		&builtinRule{
+			id:          "missing-pod-reference",
+			description: "Pods should only reference ConfigMaps, Secrets, ServiceAccounts and PersistentVolumeClaims that exist",
+			severity:    SeverityError,
+			check:       checkPodReferences,
+		},
+		&builtinRule{
+			id:          "ingress-missing-service",
+			description: "Ingress backends should point to existing Services",
+			severity:    SeverityError,
+			check:       checkIngressBackends,
+		},
+		&builtinRule{
+			id:          "dangling-owner-reference",
+			description: "Owner references should point to resources that exist",
+			severity:    SeverityWarning,
+			check:       checkOwnerReferences,
+		},
+	}
+}
+
+func checkPodReferences(ctx *Context, resource *models.KubernetesResource) []string {
+	podSpec := podSpecOf(resource)
+	if podSpec == nil {
+		return nil
+	}
+
+	var messages []string
+	seen := make(map[string]bool)
+	for _, ref := range graph.PodReferences(podSpec) {
+		key := ref.Kind + "/" + ref.Name
+		if ref.Optional || seen[key] || !ctx.Captured(ref.Kind) {
+			continue
+		}
+		seen[key] = true
+		if !ctx.Exists(ref.Kind, resource.Namespace(), ref.Name) {
+			messages = append(messages, fmt.Sprintf("references missing %s %q", ref.Kind, ref.Name))
+		}
+	}
+	return messages
+}
+
+func checkIngressBackends(ctx *Context, resource *models.KubernetesResource) []string {
+	if resource.Kind != "Ingress" || !ctx.Captured("Service") {
+		return nil
+	}
+	spec, err := resource.Spec.Decode()
+	if err != nil {
+		return nil
+	}
+
+	var services []string
+	addBackend := func(backend map[string]interface{}) {
+		if service, ok := backend["service"].(map[string]interface{}); ok {
+			if name, _ := service["name"].(string); name != "" {
+				services = append(services, name)
+			}
+		}
+		if name, _ := backend["serviceName"].(string); name != "" {
+			services = append(services, name)
+		}
+	}
+
+	for _, field := range []string{"defaultBackend", "backend"} {
+		if backend, ok := spec[field].(map[string]interface{}); ok {
+			addBackend(backend)
+		}
+	}
+	rules, _ := spec["rules"].([]interface{})
+	for _, item := range rules {
+		rule, _ := item.(map[string]interface{})
+		http, _ := rule["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for _, p := range paths {
+			path, _ := p.(map[string]interface{})
+			if backend, ok := path["backend"].(map[string]interface{}); ok {
+				addBackend(backend)
+			}
+		}
+	}
+
+	var messages []string
+	seen := make(map[string]bool)
+	for _, name := range services {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !ctx.Exists("Service", resource.Namespace(), name) {
+			messages = append(messages, fmt.Sprintf("backend Service %q does not exist", name))
+		}
+	}
+	return messages
+}
+
+func checkOwnerReferences(ctx *Context, resource *models.KubernetesResource) []string {
+	refs, err := resource.KubernetesResourceMeta.DecodeOwnerReferences()
+	if err != nil {
+		return nil
+	}
+	var messages []string
+	for _, ref := range refs {
+		if ref.UID == "" || ctx.HasUID(ref.UID) || !ctx.Captured(ref.Kind) {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("owner %s/%s (uid %s) is not in the snapshot", ref.Kind, ref.Name, ref.UID))
+	}
+	return messages
+}
@@ -0,0 +1,119 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// regoRule reports every message in a policy's deny set. The policy sees
+// the resource as input, with decoded spec and status, the same object CEL
+// rules see as `resource`.
+type regoRule struct {
+	id          string
+	description string
+	severity    string
+	query       rego.PreparedEvalQuery
+}
+
+func (r *regoRule) ID() string          { return r.id }
+func (r *regoRule) Description() string { return r.description }
+func (r *regoRule) Severity() string    { return r.severity }
+
+func (r *regoRule) Check(_ *Context, resource *models.KubernetesResource) ([]string, error) {
+	results, err := r.query.Eval(context.Background(), rego.EvalInput(resource.Object()))
+	if err != nil {
+		return nil, err
+	}
+	var messages []string
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("deny is %v, not a set of messages", expression.Value)
+			}
+			for _, value := range values {
+				messages = append(messages, regoMessage(value))
+			}
+		}
+	}
+	return messages, nil
+}
+
+// regoMessage accepts plain strings and conftest-style {"msg": ...}
+// objects; anything else is reported as JSON.
+func regoMessage(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if msg, ok := v["msg"].(string); ok {
+			return msg
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// loadRegoRule reads a Rego v1 policy that defines a deny set. The package
+// path is the rule ID; the package's METADATA title or description and a
+// custom severity describe it.
+func loadRegoRule(path string) (Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	module, err := ast.ParseModuleWithOpts(path, string(data), ast.ParserOptions{ProcessAnnotation: true, RegoVersion: ast.RegoV1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+
+	packagePath := module.Package.Path.String()
+	rule := &regoRule{id: packagePath[len("data."):], severity: SeverityWarning}
+	for _, annotation := range module.Annotations {
+		if annotation.Scope != "package" {
+			continue
+		}
+		rule.description = annotation.Description
+		if rule.description == "" {
+			rule.description = annotation.Title
+		}
+		if severity, ok := annotation.Custom["severity"]; ok {
+			rule.severity = fmt.Sprint(severity)
+		}
+	}
+	if rule.description == "" {
+		rule.description = fmt.Sprintf("Rego policy %s", rule.id)
+	}
+	if !ValidSeverity(rule.severity) {
+		return nil, fmt.Errorf("%s: rule %s has invalid severity %q", path, rule.id, rule.severity)
+	}
+
+	definesDeny := false
+	for _, r := range module.Rules {
+		definesDeny = definesDeny || r.Head.Ref().String() == "deny"
+	}
+	if !definesDeny {
+		return nil, fmt.Errorf("%s: package %s defines no deny rule", path, rule.id)
+	}
+
+	// Strict builtin errors report a broken policy, such as arithmetic on a
+	// string, instead of treating the failing expression as undefined.
+	rule.query, err = rego.New(
+		rego.Query(packagePath+".deny"),
+		rego.ParsedModule(module),
+		rego.StrictBuiltinErrors(true),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("%s: rule %s: %w", path, rule.id, err)
+	}
+	return rule, nil
+}
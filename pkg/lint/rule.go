@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+var severityRank = map[string]int{
+	SeverityNote:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// Rule checks one resource and returns a message per problem found. An
+// error means the rule itself could not be evaluated; it is reported as an
+// error-severity finding whatever the rule's own severity.
+type Rule interface {
+	ID() string
+	Description() string
+	Severity() string
+	Check(ctx *Context, resource *models.KubernetesResource) ([]string, error)
+}
+
+type Context struct {
+	Resources []*models.KubernetesResource
+
+	keys  map[string]bool
+	uids  map[string]bool
+	kinds map[string]bool
+}
+
+func (c *Context) index() {
+	if c.keys != nil {
+		return
+	}
+	c.keys = make(map[string]bool)
+	c.uids = make(map[string]bool)
+	c.kinds = make(map[string]bool)
+	for _, resource := range c.Resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		c.keys[resource.Kind+"/"+resource.Namespace()+"/"+resource.Name()] = true
+		c.uids[resource.UID()] = true
+		c.kinds[resource.Kind] = true
+	}
+}
+
+// Exists reports whether the snapshot holds the named resource.
+func (c *Context) Exists(kind, namespace, name string) bool {
+	c.index()
+	return c.keys[kind+"/"+namespace+"/"+name]
+}
+
+func (c *Context) HasUID(uid string) bool {
+	c.index()
+	return c.uids[uid]
+}
+
+// Captured reports whether any resource of the kind is in the snapshot. A
+// reference to a kind that was never captured cannot be judged missing.
+func (c *Context) Captured(kind string) bool {
+	c.index()
+	return c.kinds[kind]
+}
+
+type Finding struct {
+	RuleID    string `json:"rule"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+type Engine struct {
+	Rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{Rules: rules}
+}
+
+func (e *Engine) Run(resources []*models.KubernetesResource) []Finding {
+	return e.RunScoped(resources, resources)
+}
+
+// RunScoped checks only the scoped resources while rules still see every
+// resource, so references that cross a namespace filter are resolved.
+func (e *Engine) RunScoped(all, scoped []*models.KubernetesResource) []Finding {
+	ctx := &Context{Resources: all}
+	findings := []Finding{}
+
+	for _, resource := range scoped {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		for _, rule := range e.Rules {
+			messages, err := rule.Check(ctx, resource)
+			if err != nil {
+				findings = append(findings, Finding{
+					RuleID:    rule.ID(),
+					Severity:  SeverityError,
+					Kind:      resource.Kind,
+					Namespace: resource.Namespace(),
+					Name:      resource.Name(),
+					Message:   fmt.Sprintf("rule failed to evaluate: %v", err),
+				})
+			}
+			for _, message := range messages {
+				findings = append(findings, Finding{
+					RuleID:    rule.ID(),
+					Severity:  rule.Severity(),
+					Kind:      resource.Kind,
+					Namespace: resource.Namespace(),
+					Name:      resource.Name(),
+					Message:   message,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return findings
+}
+
+// AtLeast reports whether any finding is at or above the given severity.
+func AtLeast(findings []Finding, severity string) bool {
+	threshold, ok := severityRank[severity]
+	if !ok {
+		return false
+	}
+	for _, finding := range findings {
+		if severityRank[finding.Severity] >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidSeverity(severity string) bool {
+	_, ok := severityRank[severity]
+	return ok
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// maxWarnings bounds what a long-running process keeps.
+	maxWarnings = 100
+)
+
+var (
+	interactive atomic.Bool
+	warnings    = &warningRecorder{}
+)
+
+// ParseLevel accepts debug, info, warn (or warning) and error.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", value)
+}
+
+// Setup makes a text or JSON handler writing to w the default slog logger.
+// Progress spinners are only shown for text logs going to a terminal.
+func Setup(w io.Writer, level slog.Level, format string) error {
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, handlerOptions)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	default:
+		return fmt.Errorf("unknown log format %q (use text or json)", format)
+	}
+	slog.SetDefault(slog.New(&recordingHandler{Handler: handler, recorder: warnings}))
+
+	file, ok := w.(*os.File)
+	interactive.Store(format != FormatJSON && ok && IsTerminal(file))
+	return nil
+}
+
+// Interactive reports whether logs go to a terminal as text, where
+// carriage-return progress output is safe.
+func Interactive() bool {
+	return interactive.Load()
+}
+
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Warnings returns the warnings logged so far, whatever the log level, as
+// "message: key=value, ..." strings.
+func Warnings() []string {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	return append([]string{}, warnings.messages...)
+}
+
+type warningRecorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// recordingHandler passes records on to Handler and keeps the text of
+// every warning.
+type recordingHandler struct {
+	slog.Handler
+	recorder *warningRecorder
+	attrs    []slog.Attr
+}
+
+func (h *recordingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level == slog.LevelWarn || h.Handler.Enabled(ctx, level)
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level == slog.LevelWarn {
+		var parts []string
+		for _, attr := range h.attrs {
+			parts = append(parts, attr.String())
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			parts = append(parts, attr.String())
+			return true
+		})
+		message := record.Message
+		if len(parts) > 0 {
+			message += ": " + strings.Join(parts, ", ")
+		}
+		h.recorder.mu.Lock()
+		if len(h.recorder.messages) < maxWarnings {
+			h.recorder.messages = append(h.recorder.messages, message)
+		}
+		h.recorder.mu.Unlock()
+	}
+	if !h.Handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordingHandler{
+		Handler:  h.Handler.WithAttrs(attrs),
+		recorder: h.recorder,
+		attrs:    append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return &recordingHandler{Handler: h.Handler.WithGroup(name), recorder: h.recorder, attrs: h.attrs}
+}
@@ -0,0 +1,252 @@
+package meshery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	ImportPath = "/api/pattern/import"
+
+	DefaultRetries    = 3
+	DefaultChunkSize  = 256 * 1024
+	defaultRetryDelay = time.Second
+)
+
+// Client pushes designs to a Meshery server's import API.
+type Client struct {
+	BaseURL string
+	Token   string
+	// Provider is sent as the meshery-provider cookie when set.
+	Provider string
+	// Retries is how many times a request the server did not process is
+	// repeated: a connection failure before the request was sent, or a 429
+	// or 503 response. An import is not idempotent, so nothing else is
+	// retried.
+	Retries int
+	// ChunkSize is how much of the encoded file is written per chunk of the
+	// chunked request body.
+	ChunkSize  int
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		Retries:    DefaultRetries,
+		ChunkSize:  DefaultChunkSize,
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// ImportResult identifies what the server created.
+type ImportResult struct {
+	ID           string
+	Name         string
+	ConnectionID string
+}
+
+// LoadToken accepts a token, or the path of a file holding one. Files in
+// mesheryctl's auth.json form also supply the provider.
+func LoadToken(value string) (token, provider string, err error) {
+	if _, statErr := os.Stat(value); statErr != nil {
+		return value, "", nil
+	}
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	var auth struct {
+		Token    string `json:"token"`
+		Provider string `json:"meshery-provider"`
+	}
+	if json.Unmarshal(data, &auth) == nil && auth.Token != "" {
+		return auth.Token, auth.Provider, nil
+	}
+	return strings.TrimSpace(string(data)), "", nil
+}
+
+// ImportDesign uploads a design file. The body is streamed with chunked
+// transfer encoding, base64-encoding the file as it goes, so nothing larger
+// than one chunk is buffered on top of the design itself.
+func (c *Client) ImportDesign(ctx context.Context, name, fileName string, data []byte) (*ImportResult, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			delay := defaultRetryDelay << (attempt - 1)
+			var retryErr *retryableError
+			if errors.As(lastErr, &retryErr) && retryErr.after > 0 {
+				delay = retryErr.after
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		result, err := c.importOnce(ctx, name, fileName, data)
+		if err == nil {
+			return result, nil
+		}
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("giving up after %d attempts: %w", c.Retries+1, lastErr)
+}
+
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func (c *Client) importOnce(ctx context.Context, name, fileName string, data []byte) (*ImportResult, error) {
+	body, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(c.writePayload(writer, name, fileName, data))
+	}()
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+ImportPath, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.AddCookie(&http.Cookie{Name: "token", Value: c.Token})
+	}
+	if c.Provider != "" {
+		req.AddCookie(&http.Cookie{Name: "meshery-provider", Value: c.Provider})
+	}
+
+	var sent atomic.Bool
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaders: func() { sent.Store(true) },
+	}))
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if sent.Load() {
+			// The server may have created the design already.
+			return nil, err
+		}
+		return nil, &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read meshery response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("meshery server rejected the token (%s)", resp.Status)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		err := &retryableError{err: fmt.Errorf("meshery server returned %s: %s", resp.Status, summarize(respBody))}
+		if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil {
+			err.after = time.Duration(seconds) * time.Second
+		}
+		return nil, err
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("meshery server returned %s: %s", resp.Status, summarize(respBody))
+	}
+	return parseResult(respBody), nil
+}
+
+// writePayload writes the import request:
+// {"name": ..., "file_name": ..., "file": "<base64>"}.
+func (c *Client) writePayload(w io.Writer, name, fileName string, data []byte) error {
+	nameJSON, _ := json.Marshal(name)
+	fileNameJSON, _ := json.Marshal(fileName)
+	if _, err := fmt.Fprintf(w, `{"name":%s,"file_name":%s,"file":"`, nameJSON, fileNameJSON); err != nil {
+		return err
+	}
+
+	chunkSize := c.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	// Whole base64 quanta per chunk keep the encoding free of padding
+	// until the last one.
+	chunkSize -= chunkSize % 3
+	if chunkSize == 0 {
+		chunkSize = 3
+	}
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(chunkSize))
+	for start := 0; start < len(data); start += chunkSize {
+		end := start + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		n := base64.StdEncoding.EncodedLen(end - start)
+		base64.StdEncoding.Encode(encoded[:n], data[start:end])
+		if _, err := w.Write(encoded[:n]); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, `"}`)
+	return err
+}
+
+// parseResult reads the ID of the created design from either a single
+// object or the list of designs Meshery returns from an import.
+func parseResult(body []byte) *ImportResult {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return &ImportResult{}
+	}
+	if list, ok := decoded.([]interface{}); ok {
+		if len(list) == 0 {
+			return &ImportResult{}
+		}
+		decoded = list[0]
+	}
+	object, _ := decoded.(map[string]interface{})
+	return &ImportResult{
+		ID:           stringField(object, "id"),
+		Name:         stringField(object, "name"),
+		ConnectionID: stringField(object, "connection_id"),
+	}
+}
+
+func stringField(object map[string]interface{}, key string) string {
+	if value, ok := object[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func summarize(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if len(text) > 200 {
+		text = text[:200] + "..."
+	}
+	if text == "" {
+		return "empty response"
+	}
+	return text
+}
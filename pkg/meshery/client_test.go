@@ -0,0 +1,144 @@
+package meshery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(url string) *Client {
+	client := NewClient(url, "secret")
+	client.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	return client
+}
+
+func TestImportDesignStreamsChunkedPayload(t *testing.T) {
+	data := []byte(strings.Repeat("design: payload\n", 100) + "tail")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ImportPath || r.Method != http.MethodPost {
+			t.Errorf("got %s %s, want POST %s", r.Method, r.URL.Path, ImportPath)
+		}
+		if len(r.TransferEncoding) == 0 || r.TransferEncoding[0] != "chunked" {
+			t.Errorf("transfer encoding = %v, want chunked", r.TransferEncoding)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var payload struct {
+			Name     string `json:"name"`
+			FileName string `json:"file_name"`
+			File     string `json:"file"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(payload.File)
+		if err != nil {
+			t.Errorf("decoding file: %v", err)
+		}
+		if string(decoded) != string(data) || payload.Name != "demo" || payload.FileName != "demo.yaml" {
+			t.Errorf("payload = %q %q %d bytes, want demo demo.yaml %d bytes", payload.Name, payload.FileName, len(decoded), len(data))
+		}
+		w.Write([]byte(`{"id":"abc"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	// Not a multiple of three, so the chunk size is rounded down.
+	client.ChunkSize = 100
+	result, err := client.ImportDesign(context.Background(), "demo", "demo.yaml", data)
+	if err != nil {
+		t.Fatalf("ImportDesign: %v", err)
+	}
+	if result.ID != "abc" {
+		t.Errorf("ID = %q, want abc", result.ID)
+	}
+}
+
+func TestImportDesignRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "429 then success", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantCalls: 2},
+		{name: "503 then success", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, wantCalls: 2},
+		{name: "500 is not retried", statuses: []int{http.StatusInternalServerError}, wantCalls: 1, wantErr: true},
+		{name: "401 is not retried", statuses: []int{http.StatusUnauthorized}, wantCalls: 1, wantErr: true},
+		{name: "403 is not retried", statuses: []int{http.StatusForbidden}, wantCalls: 1, wantErr: true},
+		{name: "gives up", statuses: []int{503, 503}, wantCalls: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				status := tt.statuses[len(tt.statuses)-1]
+				if int(call) <= len(tt.statuses) {
+					status = tt.statuses[call-1]
+				}
+				if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+					w.Header().Set("Retry-After", "1")
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(`{"id":"abc"}`))
+			}))
+			defer server.Close()
+
+			client := newTestClient(server.URL)
+			client.Retries = len(tt.statuses) - 1
+			started := time.Now()
+			_, err := client.ImportDesign(context.Background(), "demo", "demo.yaml", []byte("x"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantCalls > 1 && time.Since(started) < time.Second {
+				t.Errorf("retried after %s, before Retry-After", time.Since(started))
+			}
+		})
+	}
+}
+
+func TestImportDesignRetriesUnsentRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := newTestClient(url)
+	client.Retries = 1
+	_, err := client.ImportDesign(context.Background(), "demo", "demo.yaml", []byte("x"))
+	if err == nil || !strings.Contains(err.Error(), "giving up after 2 attempts") {
+		t.Errorf("err = %v, want a retried connection failure", err)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want ImportResult
+	}{
+		{name: "object", body: `{"id":"a","name":"n","connection_id":"c"}`, want: ImportResult{ID: "a", Name: "n", ConnectionID: "c"}},
+		{name: "list", body: `[{"id":"a","name":"n"},{"id":"b"}]`, want: ImportResult{ID: "a", Name: "n"}},
+		{name: "empty list", body: `[]`},
+		{name: "not JSON", body: `ok`},
+		{name: "non-string id", body: `{"id":7}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseResult([]byte(tt.body)); *got != tt.want {
+				t.Errorf("parseResult(%s) = %+v, want %+v", tt.body, *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package meshsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	DefaultSubject = "meshery.meshsync.core"
+
+	ObjectTypeSingle = "single"
+	EventTypeAdded   = "ADDED"
+)
+
+// Event is the envelope MeshSync publishes resources in, and the one
+// CollectResources parses.
+type Event struct {
+	Object     *models.KubernetesResource `json:"Object"`
+	ObjectType string                     `json:"ObjectType"`
+	EventType  string                     `json:"EventType"`
+}
+
+type PublishOptions struct {
+	Subject string
+	// Interval paces messages for consumers that cannot absorb a burst.
+	Interval time.Duration
+}
+
+// PublishResources emits every resource as an ADDED event, as MeshSync does
+// on startup, and flushes the connection so all messages reach the broker.
+func PublishResources(nc *nats.Conn, resources []*models.KubernetesResource, opts PublishOptions) (int, error) {
+	subject := opts.Subject
+	if subject == "" {
+		subject = DefaultSubject
+	}
+
+	published := 0
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		data, err := json.Marshal(Event{Object: resource, ObjectType: ObjectTypeSingle, EventType: EventTypeAdded})
+		if err != nil {
+			return published, fmt.Errorf("failed to marshal %s event: %w", resource.Kind, err)
+		}
+		if err := nc.Publish(subject, data); err != nil {
+			return published, fmt.Errorf("failed to publish to %s: %w", subject, err)
+		}
+		published++
+		if opts.Interval > 0 {
+			time.Sleep(opts.Interval)
+		}
+	}
+
+	if err := nc.Flush(); err != nil {
+		return published, fmt.Errorf("failed to flush NATS connection: %w", err)
+	}
+	return published, nil
+}
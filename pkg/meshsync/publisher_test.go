@@ -0,0 +1,109 @@
+package meshsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	natsd "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestPublishResourcesRoundTrip(t *testing.T) {
+	server, err := natsd.NewServer(&natsd.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Start()
+	defer server.Shutdown()
+	if !server.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+
+	// Collect only settles once it has seen more than ten resources.
+	var resources []*models.KubernetesResource
+	for i := 0; i < 12; i++ {
+		resources = append(resources, &models.KubernetesResource{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+				Name:      fmt.Sprintf("web-%d", i),
+				Namespace: "default",
+				Labels:    []*models.KubernetesKeyValue{{Key: "app", Value: "web"}},
+			},
+			Spec:   &models.KubernetesResourceSpec{Attribute: `{"nodeName":"node-1"}`},
+			Status: &models.KubernetesResourceStatus{Attribute: `{"phase":"Running"}`},
+		})
+	}
+	resources = append(resources, &models.KubernetesResource{
+		APIVersion:             "apps/v1",
+		Kind:                   "Deployment",
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{Name: "web", Namespace: "default"},
+		Spec:                   &models.KubernetesResourceSpec{Attribute: `{"replicas":12}`},
+	})
+
+	options := models.NewDefaultOptions()
+	options.QuietMode = true
+	options.CollectionTime = 20 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	type result struct {
+		collection *Collection
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
+		collection, err := Collect(ctx, server.ClientURL(), options)
+		done <- result{collection, err}
+	}()
+
+	// Publish only once Collect has subscribed to all of its topics.
+	for deadline := time.Now().Add(5 * time.Second); server.NumSubscriptions() < 4; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Collect did not subscribe")
+		}
+	}
+	nc, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	published, err := PublishResources(nc, append(resources, nil), PublishOptions{})
+	if err != nil {
+		t.Fatalf("PublishResources: %v", err)
+	}
+	if published != len(resources) {
+		t.Errorf("published %d resources, want %d", published, len(resources))
+	}
+
+	got := <-done
+	if got.err != nil {
+		t.Fatalf("Collect: %v", got.err)
+	}
+	if got.collection.Status != CollectionComplete {
+		t.Errorf("collection status = %s, want %s", got.collection.Status, CollectionComplete)
+	}
+	if want, have := sortedJSON(t, resources), sortedJSON(t, got.collection.Resources); !reflect.DeepEqual(have, want) {
+		t.Errorf("collected resources differ from published ones:\ngot  %v\nwant %v", have, want)
+	}
+}
+
+func sortedJSON(t *testing.T, resources []*models.KubernetesResource) []string {
+	t.Helper()
+	var encoded []string
+	for _, resource := range resources {
+		data, err := json.Marshal(resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded = append(encoded, string(data))
+	}
+	sort.Strings(encoded)
+	return encoded
+}
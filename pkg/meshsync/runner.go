@@ -3,6 +3,7 @@ package meshsync
 import (
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,9 +13,7 @@ import (
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
 )
 func Run(brokerURL, meshsyncPath string, options *models.Options) (*exec.Cmd, error) {
-	if options.VerboseMode {
-		fmt.Printf("Starting MeshSync from: %s\n", meshsyncPath)
-	}
+	slog.Debug("Starting MeshSync", "path", meshsyncPath)
 	if _, err := os.Stat(meshsyncPath); err != nil {
 		return nil, fmt.Errorf("MeshSync binary not found at %s: %w", meshsyncPath, err)
 	}
@@ -28,19 +27,16 @@ func Run(brokerURL, meshsyncPath string, options *models.Options) (*exec.Cmd, er
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true, 
 	}
+	var logFile *os.File
+	logPath := filepath.Join(os.TempDir(), "meshsync.log")
 	if options.VerboseMode {
-		logPath := filepath.Join(os.TempDir(), "meshsync.log")
-		logFile, err := os.Create(logPath)
+		var err error
+		logFile, err = os.Create(logPath)
 		if err == nil {
 			cmd.Stdout = logFile
 			cmd.Stderr = logFile
-			go func() {
-				cmd.Wait()
-				logFile.Close()
-				fmt.Printf("MeshSync logs available at: %s\n", logPath)
-			}()
 		} else {
-			cmd.Stdout = os.Stdout
+			cmd.Stdout = os.Stderr
 			cmd.Stderr = os.Stderr
 		}
 	} else {
@@ -48,15 +44,22 @@ func Run(brokerURL, meshsyncPath string, options *models.Options) (*exec.Cmd, er
 		cmd.Stderr = io.Discard
 	}
 	if err := cmd.Start(); err != nil {
+		if logFile != nil {
+			logFile.Close()
+		}
 		return nil, fmt.Errorf("failed to start MeshSync: %w", err)
 	}
-	if options.VerboseMode {
-		fmt.Print("Waiting for MeshSync to initialize...")
-	}
+	// Reap MeshSync when it exits, so a long-running daemon does not
+	// collect zombies and Alive sees the exit.
+	go func() {
+		cmd.Wait()
+		if logFile != nil {
+			logFile.Close()
+			slog.Debug("MeshSync exited", "log", logPath)
+		}
+	}()
+	slog.Debug("Waiting for MeshSync to initialize")
 	time.Sleep(2 * time.Second)
-	if options.VerboseMode {
-		fmt.Println(" ✓")
-	}
 	if cmd.Process == nil {
 		return nil, fmt.Errorf("MeshSync process exited immediately")
 	}
@@ -78,7 +81,6 @@ func KillProcessGroup(cmd *exec.Cmd) error {
     } else {
         cmd.Process.Kill()
     }
-    exec.Command("pkill", "-9", "-f", "meshsync").Run() 
     time.Sleep(500 * time.Millisecond)
     return nil
 }
@@ -0,0 +1,147 @@
+package meshsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/metrics"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	EventTypeModified = "MODIFIED"
+	EventTypeDeleted  = "DELETED"
+)
+
+// Store mirrors cluster state from a long-lived subscription. Unlike
+// CollectResources, which only sees the ADDED burst MeshSync publishes on
+// startup, it applies updates and deletions so it stays current while
+// MeshSync keeps running.
+type Store struct {
+	nc        *nats.Conn
+	subs      []*nats.Subscription
+	mu        sync.Mutex
+	resources map[string]*models.KubernetesResource
+	updated   time.Time
+}
+
+func NewStore(natsURL string, options *models.Options) (*Store, error) {
+	nc, err := nats.Connect(natsURL,
+		nats.ReconnectWait(300*time.Millisecond),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.Timeout(3*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	s := &Store{nc: nc, resources: make(map[string]*models.KubernetesResource)}
+	for _, topic := range []string{DefaultSubject, DefaultSubject + ".resource", "meshery.meshsync", "meshery.meshsync.resource"} {
+		sub, err := nc.Subscribe(topic, s.handle)
+		if err != nil {
+			slog.Warn("Failed to subscribe", "topic", topic, "error", err)
+			continue
+		}
+		s.subs = append(s.subs, sub)
+	}
+	if len(s.subs) == 0 {
+		nc.Close()
+		return nil, fmt.Errorf("failed to subscribe to any NATS topics")
+	}
+	return s, nil
+}
+
+func (s *Store) handle(msg *nats.Msg) {
+	metrics.MessageReceived()
+	var event Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil || event.Object == nil {
+		var direct models.KubernetesResource
+		if json.Unmarshal(msg.Data, &direct) != nil {
+			metrics.UnmarshalFailed()
+			return
+		}
+		event = Event{Object: &direct, EventType: EventTypeAdded}
+	}
+	metrics.EventReceived(event.EventType)
+	resource := event.Object
+	if resource.KubernetesResourceMeta == nil {
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s", resource.Kind, resource.KubernetesResourceMeta.Namespace, resource.KubernetesResourceMeta.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch event.EventType {
+	case "", EventTypeAdded, EventTypeModified:
+		s.resources[key] = resource
+	case EventTypeDeleted:
+		delete(s.resources, key)
+	default:
+		return
+	}
+	s.updated = time.Now()
+}
+
+// WaitForSync blocks until MeshSync's initial burst has settled: the store
+// is non-empty and quiet for a second, or timeout has passed.
+func (s *Store) WaitForSync(ctx context.Context, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(300 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			if s.Len() == 0 {
+				return fmt.Errorf("no resources received from MeshSync within %s", timeout)
+			}
+			return nil
+		case <-ticker.C:
+			s.mu.Lock()
+			settled := len(s.resources) > 0 && time.Since(s.updated) >= time.Second
+			s.mu.Unlock()
+			if settled {
+				return nil
+			}
+		}
+	}
+}
+
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.resources)
+}
+
+// Resources returns the current state, filtered as CollectResources does.
+func (s *Store) Resources(options *models.Options) []*models.KubernetesResource {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.resources))
+	for key := range s.resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	resources := make([]*models.KubernetesResource, 0, len(keys))
+	for _, key := range keys {
+		resources = append(resources, s.resources[key])
+	}
+	s.mu.Unlock()
+	return utils.FilterResources(resources, options)
+}
+
+func (s *Store) Close() {
+	for _, sub := range s.subs {
+		sub.Unsubscribe()
+	}
+	s.nc.Close()
+}
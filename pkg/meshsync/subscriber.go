@@ -4,17 +4,49 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/metrics"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
 	"github.com/nats-io/nats.go"
 )
+// How a collection ended.
+const (
+	// CollectionComplete means the resource stream settled.
+	CollectionComplete = "complete"
+	// CollectionTimeout means the collection time ran out first, so the
+	// snapshot may be missing resources.
+	CollectionTimeout = "timeout"
+	// CollectionInterrupted means the context was cancelled first.
+	CollectionInterrupted = "interrupted"
+)
+
+type Collection struct {
+	Resources []*models.KubernetesResource
+	Status    string
+}
+
 func CollectResources(ctx context.Context, natsURL string, options *models.Options) ([]*models.KubernetesResource, error) {
+	collection, err := Collect(ctx, natsURL, options)
+	if err != nil {
+		return nil, err
+	}
+	return collection.Resources, nil
+}
+
+// Collect gathers resources like CollectResources and also reports how
+// the collection ended.
+func Collect(ctx context.Context, natsURL string, options *models.Options) (*Collection, error) {
 	if options.PreviewMode {
-		return previewResources(options)
+		resources, err := previewResources(options)
+		if err != nil {
+			return nil, err
+		}
+		return &Collection{Resources: resources, Status: CollectionComplete}, nil
 	}
 	nc, err := nats.Connect(natsURL, 
 		nats.ReconnectWait(300*time.Millisecond),  
@@ -22,9 +54,7 @@ func CollectResources(ctx context.Context, natsURL string, options *models.Optio
 		nats.RetryOnFailedConnect(true),
 		nats.Timeout(3*time.Second),               
 		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
-			if options.VerboseMode {
-				fmt.Printf("NATS disconnected: %v\n", err)
-			}
+			slog.Debug("NATS disconnected", "error", err)
 		}),
 	)
 	if err != nil {
@@ -34,7 +64,7 @@ func CollectResources(ctx context.Context, natsURL string, options *models.Optio
 	var resources []*models.KubernetesResource
 	var resourcesMutex sync.Mutex
 	resourceChan := make(chan *models.KubernetesResource, 1000)
-	doneChan := make(chan bool, 1)
+	doneChan := make(chan string, 1)
 	resourceCount := atomic.Int32{}
 	progressDone := make(chan bool, 1)
 	if !options.QuietMode {
@@ -48,34 +78,30 @@ func CollectResources(ctx context.Context, natsURL string, options *models.Optio
 	}
 	var subs []*nats.Subscription
 	for _, topic := range topics {
-		if options.VerboseMode {
-			fmt.Printf("Subscribing to NATS topic: %s\n", topic)
-		}
+		slog.Debug("Subscribing to NATS topic", "topic", topic)
 		sub, err := nc.Subscribe(topic, func(msg *nats.Msg) {
-			var message struct {
-				Object *models.KubernetesResource `json:"Object"`
-				ObjectType string                 `json:"ObjectType"`
-				EventType string                  `json:"EventType"`
-			}
+			metrics.MessageReceived()
+			var message Event
 			if err := json.Unmarshal(msg.Data, &message); err != nil {
 				var directResource models.KubernetesResource
 				if err2 := json.Unmarshal(msg.Data, &directResource); err2 != nil {
-					if options.VerboseMode {
-						fmt.Printf("Warning: Could not unmarshal message: %v\n", err2)
-					}
+					metrics.UnmarshalFailed()
+					slog.Debug("Could not unmarshal message", "subject", msg.Subject, "error", err2)
 					return
 				}
+				metrics.EventReceived(EventTypeAdded)
 				resourceChan <- &directResource
 				return
 			}
-			if message.Object != nil && (message.EventType == "" || message.EventType == "ADDED") {
+			if message.Object != nil {
+				metrics.EventReceived(message.EventType)
+			}
+			if message.Object != nil && (message.EventType == "" || message.EventType == EventTypeAdded) {
 				resourceChan <- message.Object
 			}
 		})
 		if err != nil {
-			if options.VerboseMode {
-				fmt.Printf("Warning: Failed to subscribe to %s: %v\n", topic, err)
-			}
+			slog.Warn("Failed to subscribe", "topic", topic, "error", err)
 			continue
 		}
 		subs = append(subs, sub)
@@ -107,7 +133,7 @@ func CollectResources(ctx context.Context, natsURL string, options *models.Optio
 					}
 					if stableCount >= 3 { 
 						select {
-						case doneChan <- true:
+						case doneChan <- CollectionComplete:
 						default:
 						}
 						return
@@ -142,28 +168,25 @@ func CollectResources(ctx context.Context, natsURL string, options *models.Optio
 				}
 			case <-collectionTimer.C:
 				select {
-				case doneChan <- true:
+				case doneChan <- CollectionTimeout:
 				default:
 				}
 				return
 			case <-ctx.Done():
 				select {
-				case doneChan <- true:
+				case doneChan <- CollectionInterrupted:
 				default:
 				}
 				return
 			}
 		}
 	}()
-	<-doneChan
+	status := <-doneChan
 	collecting = false
 	close(progressDone)
 	filteredResources := utils.FilterResources(resources, options)
-	if options.VerboseMode {
-		fmt.Printf("Collected %d resources, filtered to %d resources\n", 
-			len(resources), len(filteredResources))
-	}
-	return filteredResources, nil
+	slog.Debug("Collected resources", "received", len(resources), "kept", len(filteredResources))
+	return &Collection{Resources: filteredResources, Status: status}, nil
 }
 func previewResources(options *models.Options) ([]*models.KubernetesResource, error) {
 	sampleResources := []*models.KubernetesResource{
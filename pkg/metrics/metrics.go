@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	natsd "github.com/nats-io/nats-server/v2/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const namespace = "meshsync_snapshot"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	resourcesCaptured = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "resources",
+		Help:      "Resources in the last successful snapshot, by kind.",
+	}, []string{"kind"})
+	events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_total",
+		Help:      "MeshSync events received, by type (ADDED, MODIFIED, DELETED).",
+	}, []string{"type"})
+	natsMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nats_messages_received_total",
+		Help:      "NATS messages received from MeshSync.",
+	})
+	unmarshalFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unmarshal_failures_total",
+		Help:      "NATS messages that could not be decoded as a resource or an event.",
+	})
+	collectionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "collection_duration_seconds",
+		Help:      "Time taken to collect and save a snapshot.",
+		Buckets:   []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	})
+	snapshotSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "size_bytes",
+		Help:      "Size of the last successful snapshot file.",
+	})
+	lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful snapshot.",
+	})
+	runs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runs_total",
+		Help:      "Snapshot runs, by result (success, failure).",
+	}, []string{"result"})
+	consecutiveFailures = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consecutive_failures",
+		Help:      "Snapshot runs that have failed since the last success.",
+	})
+
+	natsCollector = &natsVarzCollector{}
+)
+
+func init() {
+	registry.MustRegister(
+		resourcesCaptured, events, natsMessages, unmarshalFailures,
+		collectionDuration, snapshotSize, lastSuccess, runs, consecutiveFailures,
+		natsCollector,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	runs.WithLabelValues("success")
+	runs.WithLabelValues("failure")
+}
+
+// MessageReceived counts a NATS message from MeshSync.
+func MessageReceived() {
+	natsMessages.Inc()
+}
+
+// UnmarshalFailed counts a message that decoded as neither form.
+func UnmarshalFailed() {
+	unmarshalFailures.Inc()
+}
+
+// EventReceived counts a MeshSync event. An empty type is counted as
+// ADDED, as that is how collection treats it.
+func EventReceived(eventType string) {
+	if eventType == "" {
+		eventType = "ADDED"
+	}
+	events.WithLabelValues(eventType).Inc()
+}
+
+// SnapshotSucceeded records a saved snapshot.
+func SnapshotSucceeded(resources []*models.KubernetesResource, size int64, duration time.Duration) {
+	resourcesCaptured.Reset()
+	for _, resource := range resources {
+		if resource != nil && resource.Kind != "" {
+			resourcesCaptured.WithLabelValues(resource.Kind).Inc()
+		}
+	}
+	snapshotSize.Set(float64(size))
+	collectionDuration.Observe(duration.Seconds())
+	lastSuccess.SetToCurrentTime()
+	runs.WithLabelValues("success").Inc()
+	consecutiveFailures.Set(0)
+}
+
+// SnapshotFailed records a failed run.
+func SnapshotFailed() {
+	runs.WithLabelValues("failure").Inc()
+	consecutiveFailures.Inc()
+}
+
+// SetNATSServer points the NATS metrics at the embedded server. Between
+// runs, when there is none, pass nil and only meshsync_snapshot_nats_up
+// is reported.
+func SetNATSServer(server *natsd.Server) {
+	natsCollector.mu.Lock()
+	natsCollector.server = server
+	natsCollector.mu.Unlock()
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// natsVarzCollector re-exposes the embedded server's monitoring fields, the
+// same ones /varz serves on the monitoring port.
+type natsVarzCollector struct {
+	mu     sync.Mutex
+	server *natsd.Server
+}
+
+var (
+	natsUpDesc            = natsDesc("up", "Whether the embedded NATS server is running.")
+	natsConnectionsDesc   = natsDesc("connections", "Current client connections.")
+	natsSubscriptionsDesc = natsDesc("subscriptions", "Current subscriptions.")
+	natsInMsgsDesc        = natsDesc("in_msgs_total", "Messages received by the server.")
+	natsOutMsgsDesc       = natsDesc("out_msgs_total", "Messages sent by the server.")
+	natsInBytesDesc       = natsDesc("in_bytes_total", "Bytes received by the server.")
+	natsOutBytesDesc      = natsDesc("out_bytes_total", "Bytes sent by the server.")
+	natsSlowConsumersDesc = natsDesc("slow_consumers_total", "Clients disconnected for falling behind.")
+)
+
+func natsDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "nats", name), help, nil, nil)
+}
+
+func (c *natsVarzCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{natsUpDesc, natsConnectionsDesc, natsSubscriptionsDesc, natsInMsgsDesc, natsOutMsgsDesc, natsInBytesDesc, natsOutBytesDesc, natsSlowConsumersDesc} {
+		ch <- desc
+	}
+}
+
+func (c *natsVarzCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	server := c.server
+	c.mu.Unlock()
+
+	var varz *natsd.Varz
+	if server != nil && server.Running() {
+		varz, _ = server.Varz(nil)
+	}
+	if varz == nil {
+		ch <- prometheus.MustNewConstMetric(natsUpDesc, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(natsUpDesc, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(natsConnectionsDesc, prometheus.GaugeValue, float64(varz.Connections))
+	ch <- prometheus.MustNewConstMetric(natsSubscriptionsDesc, prometheus.GaugeValue, float64(varz.Subscriptions))
+	ch <- prometheus.MustNewConstMetric(natsInMsgsDesc, prometheus.CounterValue, float64(varz.InMsgs))
+	ch <- prometheus.MustNewConstMetric(natsOutMsgsDesc, prometheus.CounterValue, float64(varz.OutMsgs))
+	ch <- prometheus.MustNewConstMetric(natsInBytesDesc, prometheus.CounterValue, float64(varz.InBytes))
+	ch <- prometheus.MustNewConstMetric(natsOutBytesDesc, prometheus.CounterValue, float64(varz.OutBytes))
+	ch <- prometheus.MustNewConstMetric(natsSlowConsumersDesc, prometheus.CounterValue, float64(varz.SlowConsumers))
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type OwnerReference struct {
+	APIVersion         string `json:"apiVersion"`
+	Kind               string `json:"kind"`
+	Name               string `json:"name"`
+	UID                string `json:"uid"`
+	Controller         *bool  `json:"controller,omitempty"`
+	BlockOwnerDeletion *bool  `json:"blockOwnerDeletion,omitempty"`
+}
+
+func (r OwnerReference) IsController() bool {
+	return r.Controller != nil && *r.Controller
+}
+
+func (s *KubernetesResourceSpec) Decode() (map[string]interface{}, error) {
+	if s == nil {
+		return map[string]interface{}{}, nil
+	}
+	return decodeObject(s.Attribute, "spec")
+}
+
+func (s *KubernetesResourceStatus) Decode() (map[string]interface{}, error) {
+	if s == nil {
+		return map[string]interface{}{}, nil
+	}
+	return decodeObject(s.Attribute, "status")
+}
+
+func (m *KubernetesResourceObjectMeta) DecodeOwnerReferences() ([]OwnerReference, error) {
+	var refs []OwnerReference
+	if m == nil || m.OwnerReferences == "" {
+		return refs, nil
+	}
+	if err := json.Unmarshal([]byte(m.OwnerReferences), &refs); err != nil {
+		return nil, fmt.Errorf("failed to decode ownerReferences: %w", err)
+	}
+	return refs, nil
+}
+
+func (m *KubernetesResourceObjectMeta) DecodeFinalizers() ([]string, error) {
+	var finalizers []string
+	if m == nil || m.Finalizers == "" {
+		return finalizers, nil
+	}
+	if err := json.Unmarshal([]byte(m.Finalizers), &finalizers); err != nil {
+		return nil, fmt.Errorf("failed to decode finalizers: %w", err)
+	}
+	return finalizers, nil
+}
+
+func (m *KubernetesResourceObjectMeta) DecodeManagedFields() ([]map[string]interface{}, error) {
+	var fields []map[string]interface{}
+	if m == nil || m.ManagedFields == "" {
+		return fields, nil
+	}
+	if err := json.Unmarshal([]byte(m.ManagedFields), &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode managedFields: %w", err)
+	}
+	return fields, nil
+}
+
+func (m *KubernetesResourceObjectMeta) LabelMap() map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return keyValueMap(m.Labels)
+}
+
+func (m *KubernetesResourceObjectMeta) AnnotationMap() map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return keyValueMap(m.Annotations)
+}
+
+func (r *KubernetesResource) Name() string {
+	if r.KubernetesResourceMeta == nil {
+		return ""
+	}
+	return r.KubernetesResourceMeta.Name
+}
+
+func (r *KubernetesResource) Namespace() string {
+	if r.KubernetesResourceMeta == nil {
+		return ""
+	}
+	return r.KubernetesResourceMeta.Namespace
+}
+
+func (r *KubernetesResource) UID() string {
+	if r.KubernetesResourceMeta == nil {
+		return ""
+	}
+	return r.KubernetesResourceMeta.UID
+}
+
+func (r *KubernetesResource) IsControllerOwned() bool {
+	refs, err := r.KubernetesResourceMeta.DecodeOwnerReferences()
+	if err != nil {
+		return false
+	}
+	for _, ref := range refs {
+		if ref.IsController() {
+			return true
+		}
+	}
+	return false
+}
+
+// Object returns the resource as a generic Kubernetes-style object with
+// decoded spec and status, for expression languages and path queries.
+func (r *KubernetesResource) Object() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"name":        r.Name(),
+		"namespace":   r.Namespace(),
+		"uid":         r.UID(),
+		"labels":      stringMapToInterface(r.KubernetesResourceMeta.LabelMap()),
+		"annotations": stringMapToInterface(r.KubernetesResourceMeta.AnnotationMap()),
+	}
+	if r.KubernetesResourceMeta != nil {
+		metadata["creationTimestamp"] = r.KubernetesResourceMeta.CreationTimestamp
+		if refs, err := r.KubernetesResourceMeta.DecodeOwnerReferences(); err == nil && len(refs) > 0 {
+			var owners []interface{}
+			for _, ref := range refs {
+				owners = append(owners, map[string]interface{}{
+					"apiVersion": ref.APIVersion,
+					"kind":       ref.Kind,
+					"name":       ref.Name,
+					"uid":        ref.UID,
+					"controller": ref.IsController(),
+				})
+			}
+			metadata["ownerReferences"] = owners
+		}
+	}
+
+	object := map[string]interface{}{
+		"apiVersion": r.APIVersion,
+		"kind":       r.Kind,
+		"metadata":   metadata,
+	}
+	if spec, err := r.Spec.Decode(); err == nil && len(spec) > 0 {
+		object["spec"] = spec
+	}
+	if status, err := r.Status.Decode(); err == nil && len(status) > 0 {
+		object["status"] = status
+	}
+	if r.Data != "" {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(r.Data), &data); err == nil {
+			object["data"] = data
+		}
+	}
+	if r.Type != "" {
+		object["type"] = r.Type
+	}
+	return object
+}
+
+func stringMapToInterface(values map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+func keyValueMap(values []*KubernetesKeyValue) map[string]string {
+	result := make(map[string]string, len(values))
+	for _, kv := range values {
+		if kv != nil {
+			result[kv.Key] = kv.Value
+		}
+	}
+	return result
+}
+
+func decodeObject(attribute, field string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if attribute == "" {
+		return result, nil
+	}
+	if err := json.Unmarshal([]byte(attribute), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode %s attribute: %w", field, err)
+	}
+	return result, nil
+}
@@ -9,6 +9,15 @@ type Options struct {
 	OutputFile      string
 	AutoName        bool
 	OutputFormat    string
+	AttributeFormat string
+	SignKeyFile     string
+	Canonical       bool
+	DesignMetadata  bool
+	OmitTimestamps  bool
+
+	EncryptTo       []string
+	PassphraseFile  string
+	IdentityFile    string
 
 	Namespace       string
 	ResourceType    string
@@ -27,15 +36,21 @@ func NewDefaultOptions() *Options {
 	return &Options{
 		OutputFile:     "meshsync-snapshot.json",
 		OutputFormat:   "json",
+		AttributeFormat: "string",
 		CollectionTime: 5 * time.Second,
 		QuietMode:      false,
 		VerboseMode:    false,
 		PreviewMode:    false,
 		FastMode:       false,
 		ExcludeTypes:   []string{},
+		EncryptTo:      []string{},
 	}
 }
 
+func (o *Options) EncryptionEnabled() bool {
+	return len(o.EncryptTo) > 0 || o.PassphraseFile != ""
+}
+
 func (o *Options) IsTypeExcluded(resourceType string) bool {
 	for _, t := range o.ExcludeTypes {
 		if t == resourceType {
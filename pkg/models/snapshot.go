@@ -0,0 +1,12 @@
+package models
+
+type Snapshot struct {
+	Version         string                 `json:"version"`
+	Timestamp       string                 `json:"timestamp,omitempty"`
+	ClusterID       string                 `json:"cluster_id"`
+	SHA256          string                 `json:"sha256,omitempty"`
+	AttributeFormat string                 `json:"attribute_format,omitempty"`
+	PluginInfo      map[string]string      `json:"plugin_info,omitempty"`
+	FilterOptions   map[string]interface{} `json:"filter_options,omitempty"`
+	Resources       []*KubernetesResource  `json:"resources"`
+}
@@ -2,6 +2,7 @@ package nats
 
 import (
 	"fmt"
+	"log/slog"
 	"net"
 	"time"
 
@@ -19,9 +20,7 @@ func isPortInUse(port int) bool {
 }
 
 func StartServer(options *models.Options) (*natsd.Server, error) {
-	if options.VerboseMode {
-		fmt.Println("Starting temporary NATS server...")
-	}
+	slog.Debug("Starting temporary NATS server")
 
 	if isPortInUse(4222) {
 		return nil, fmt.Errorf("port 4222 is already in use, cannot start NATS server")
@@ -52,18 +51,12 @@ func StartServer(options *models.Options) (*natsd.Server, error) {
 
 	go natsServer.Start()
 
-	if options.VerboseMode {
-		fmt.Print("Waiting for NATS server to be ready...")
-	}
-
 	if !waitForServerReady(natsServer, 5*time.Second) {
 		natsServer.Shutdown()
 		return nil, fmt.Errorf("timed out waiting for NATS server to start")
 	}
 
-	if options.VerboseMode {
-		fmt.Println(" ✓")
-	}
+	slog.Debug("NATS server ready", "url", natsServer.ClientURL())
 
 	return natsServer, nil
 }
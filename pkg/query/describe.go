@@ -0,0 +1,168 @@
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/health"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func (s *Session) describe(args []string) error {
+	var sel selection
+	fs := newFlagSet("describe", &sel)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	kinds, names, err := s.resolve(positional)
+	if err != nil {
+		return err
+	}
+
+	var resources []*models.KubernetesResource
+	for _, kind := range kinds {
+		found, err := s.find(kind, names, sel)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, found...)
+	}
+	if len(resources) == 0 {
+		_, err := fmt.Fprintln(s.out, "No resources found.")
+		return err
+	}
+
+	if s.graph == nil {
+		s.graph = graph.Build(s.snapshot.Resources)
+	}
+	for i, resource := range resources {
+		if i > 0 {
+			fmt.Fprint(s.out, "\n\n")
+		}
+		if err := s.describeResource(resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Session) describeResource(resource *models.KubernetesResource) error {
+	var b strings.Builder
+	meta := resource.KubernetesResourceMeta
+
+	field := func(name, value string) {
+		fmt.Fprintf(&b, "%-18s%s\n", name+":", value)
+	}
+	field("Name", resource.Name())
+	if resource.Namespace() != "" {
+		field("Namespace", resource.Namespace())
+	}
+	field("Kind", resource.Kind)
+	field("API Version", resource.APIVersion)
+	field("UID", resource.UID())
+	writeMap(&b, "Labels", meta.LabelMap())
+	writeMap(&b, "Annotations", meta.AnnotationMap())
+	if meta.CreationTimestamp != "" {
+		field("Created", fmt.Sprintf("%s (%s before capture)", meta.CreationTimestamp, s.age(resource)))
+	}
+	if refs, err := meta.DecodeOwnerReferences(); err == nil {
+		for _, ref := range refs {
+			if ref.IsController() {
+				field("Controlled By", ref.Kind+"/"+ref.Name)
+			}
+		}
+	}
+	if status := health.Evaluate(resource); status.State != health.StateUnknown {
+		field("Health", strings.TrimSpace(status.Glyph()+" "+status.Detail))
+	}
+
+	if related := s.related(resource); len(related) > 0 {
+		b.WriteString("Related:\n")
+		for _, line := range related {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	object := resource.Object()
+	for _, section := range []string{"spec", "status", "data"} {
+		value, ok := object[section]
+		if !ok {
+			continue
+		}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to render %s: %w", section, err)
+		}
+		fmt.Fprintf(&b, "%s:\n", strings.ToUpper(section[:1])+section[1:])
+		for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	_, err := fmt.Fprint(s.out, b.String())
+	return err
+}
+
+// related lists the graph edges touching a resource, phrased from its side.
+func (s *Session) related(resource *models.KubernetesResource) []string {
+	id := graph.NodeID(resource)
+	var lines []string
+	for _, edge := range s.graph.Edges {
+		switch id {
+		case edge.From:
+			if node, ok := s.graph.Node(edge.To); ok {
+				lines = append(lines, fmt.Sprintf("%s %s", edge.Type, node.Label()))
+			}
+		case edge.To:
+			if node, ok := s.graph.Node(edge.From); ok {
+				lines = append(lines, fmt.Sprintf("%s by %s", passive(edge.Type), node.Label()))
+			}
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func passive(edgeType string) string {
+	switch edgeType {
+	case graph.EdgeOwns:
+		return "owned"
+	case graph.EdgeSelects:
+		return "selected"
+	case graph.EdgeRunsOn:
+		return "runs"
+	case graph.EdgeMounts:
+		return "mounted"
+	case graph.EdgeEnvFrom:
+		return "env source for"
+	default:
+		return "referenced"
+	}
+}
+
+func writeMap(b *strings.Builder, name string, values map[string]string) {
+	if len(values) == 0 {
+		fmt.Fprintf(b, "%-18s<none>\n", name+":")
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = name + ":"
+		}
+		fmt.Fprintf(b, "%-18s%s=%s\n", label, key, values[key])
+	}
+}
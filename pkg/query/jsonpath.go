@@ -0,0 +1,369 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is the subset of kubectl's JSONPath templates that support
+// engineers reach for: field and index access, [*], filters such as
+// [?(@.type=="Ready")], {range}...{end} and quoted string literals.
+type JSONPath struct {
+	nodes []templateNode
+}
+
+const (
+	nodeText = iota
+	nodeValue
+	nodeRange
+)
+
+type templateNode struct {
+	kind int
+	text string
+	path []pathStep
+	body []templateNode
+}
+
+type pathStep struct {
+	field  string
+	index  int
+	all    bool
+	filter *pathFilter
+}
+
+type pathFilter struct {
+	path  []pathStep
+	op    string
+	value string
+}
+
+func ParseJSONPath(template string) (*JSONPath, error) {
+	nodes, _, closed, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, fmt.Errorf("{end} without {range} in %q", template)
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseTemplate parses up to the end of the template or the first
+// unmatched {end}, returning the text that follows it.
+func parseTemplate(template string) ([]templateNode, string, bool, error) {
+	var nodes []templateNode
+	for template != "" {
+		open := strings.Index(template, "{")
+		if open < 0 {
+			nodes = append(nodes, templateNode{kind: nodeText, text: template})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, templateNode{kind: nodeText, text: template[:open]})
+		}
+		end := closingBrace(template, open)
+		if end < 0 {
+			return nil, "", false, fmt.Errorf("unclosed expression in %q", template)
+		}
+		expr := strings.TrimSpace(template[open+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case expr == "end":
+			return nodes, template, true, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", false, err
+			}
+			body, rest, closed, err := parseTemplate(template)
+			if err != nil {
+				return nil, "", false, err
+			}
+			if !closed {
+				return nil, "", false, fmt.Errorf("{range} without {end}")
+			}
+			template = rest
+			nodes = append(nodes, templateNode{kind: nodeRange, path: path, body: body})
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, "", false, fmt.Errorf("invalid string literal %s", expr)
+			}
+			nodes = append(nodes, templateNode{kind: nodeText, text: text})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, "", false, err
+			}
+			nodes = append(nodes, templateNode{kind: nodeValue, path: path})
+		}
+	}
+	return nodes, "", false, nil
+}
+
+func closingBrace(s string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+func parsePath(expr string) ([]pathStep, error) {
+	original := expr
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimPrefix(expr, "@")
+
+	var steps []pathStep
+	for expr != "" {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			name := expr[:end]
+			expr = expr[end:]
+			if name == "" {
+				continue
+			}
+			if name == "*" {
+				steps = append(steps, pathStep{all: true})
+			} else {
+				steps = append(steps, pathStep{field: name})
+			}
+		case '[':
+			end := closingBracket(expr)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", original)
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+			step, err := parseBracket(inner)
+			if err != nil {
+				return nil, fmt.Errorf("%v in %q", err, original)
+			}
+			steps = append(steps, step)
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q", original)
+		}
+	}
+	return steps, nil
+}
+
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (pathStep, error) {
+	switch {
+	case inner == "*":
+		return pathStep{all: true}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		filter, err := parseFilter(inner[2 : len(inner)-1])
+		if err != nil {
+			return pathStep{}, err
+		}
+		return pathStep{filter: filter}, nil
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		name, err := unquote(inner)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("invalid key %s", inner)
+		}
+		return pathStep{field: name}, nil
+	default:
+		index, err := strconv.Atoi(inner)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("unsupported subscript [%s]", inner)
+		}
+		return pathStep{index: index}, nil
+	}
+}
+
+func parseFilter(expr string) (*pathFilter, error) {
+	for _, op := range []string{"==", "!="} {
+		if idx := strings.Index(expr, op); idx >= 0 {
+			path, err := parsePath(strings.TrimSpace(expr[:idx]))
+			if err != nil {
+				return nil, err
+			}
+			value := strings.TrimSpace(expr[idx+len(op):])
+			if unquoted, err := unquote(value); err == nil {
+				value = unquoted
+			}
+			return &pathFilter{path: path, op: op, value: value}, nil
+		}
+	}
+	path, err := parsePath(strings.TrimSpace(expr))
+	if err != nil {
+		return nil, err
+	}
+	return &pathFilter{path: path}, nil
+}
+
+// Execute renders the template against data. Missing fields render as
+// nothing rather than failing, which suits heterogeneous snapshot objects.
+func (j *JSONPath) Execute(data interface{}) string {
+	var b strings.Builder
+	executeNodes(&b, j.nodes, data)
+	return b.String()
+}
+
+func executeNodes(b *strings.Builder, nodes []templateNode, data interface{}) {
+	for _, node := range nodes {
+		switch node.kind {
+		case nodeRange:
+			for _, item := range evaluate(node.path, data) {
+				executeNodes(b, node.body, item)
+			}
+		case nodeValue:
+			var parts []string
+			for _, value := range evaluate(node.path, data) {
+				parts = append(parts, formatValue(value))
+			}
+			b.WriteString(strings.Join(parts, " "))
+		default:
+			b.WriteString(node.text)
+		}
+	}
+}
+
+func evaluate(path []pathStep, data interface{}) []interface{} {
+	values := []interface{}{data}
+	for _, step := range path {
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, applyStep(step, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func applyStep(step pathStep, value interface{}) []interface{} {
+	switch {
+	case step.filter != nil:
+		items, _ := value.([]interface{})
+		var matched []interface{}
+		for _, item := range items {
+			if step.filter.matches(item) {
+				matched = append(matched, item)
+			}
+		}
+		return matched
+	case step.all:
+		switch v := value.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			result := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				result = append(result, v[key])
+			}
+			return result
+		}
+	case step.field != "":
+		if m, ok := value.(map[string]interface{}); ok {
+			if child, ok := m[step.field]; ok {
+				return []interface{}{child}
+			}
+		}
+	default:
+		if items, ok := value.([]interface{}); ok {
+			index := step.index
+			if index < 0 {
+				index += len(items)
+			}
+			if index >= 0 && index < len(items) {
+				return []interface{}{items[index]}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *pathFilter) matches(item interface{}) bool {
+	values := evaluate(f.path, item)
+	if f.op == "" {
+		return len(values) > 0
+	}
+	equal := len(values) > 0 && formatValue(values[0]) == f.value
+	if f.op == "==" {
+		return equal
+	}
+	return !equal
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const podJSON = `{
+  "metadata": {"name": "web-0", "labels": {"app": "web", "app.kubernetes.io/name": "shop"}},
+  "spec": {"containers": [{"name": "app", "image": "nginx:1.25"}, {"name": "sidecar", "image": "envoy"}]},
+  "status": {
+    "phase": "Running",
+    "restarts": 2,
+    "ready": true,
+    "conditions": [{"type": "Ready", "status": "True"}, {"type": "Scheduled", "status": "False"}]
+  }
+}`
+
+func TestJSONPath(t *testing.T) {
+	var pod interface{}
+	if err := json.Unmarshal([]byte(podJSON), &pod); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "{.metadata.name}", want: "web-0"},
+		{template: "{$.status.phase}", want: "Running"},
+		{template: "name={.metadata.name} phase={.status.phase}", want: "name=web-0 phase=Running"},
+		{template: "{.status.restarts}/{.status.ready}", want: "2/true"},
+		{template: "{.spec.containers[0].image}", want: "nginx:1.25"},
+		{template: "{.spec.containers[-1].name}", want: "sidecar"},
+		{template: "{.spec.containers[5].name}", want: ""},
+		{template: "{.spec.containers[*].name}", want: "app sidecar"},
+		{template: "{.metadata.labels.*}", want: "web shop"},
+		{template: "{.metadata.labels['app.kubernetes.io/name']}", want: "shop"},
+		{template: `{.status.conditions[?(@.type=="Ready")].status}`, want: "True"},
+		{template: `{.status.conditions[?(@.status!='True')].type}`, want: "Scheduled"},
+		{template: `{.spec.containers[?(@.image)].name}`, want: "app sidecar"},
+		{template: `{range .spec.containers[*]}{.name}{"\t"}{.image}{"\n"}{end}`, want: "app\tnginx:1.25\nsidecar\tenvoy\n"},
+		{template: "{.metadata.missing}", want: ""},
+		{template: "{.status.conditions[0]}", want: `{"status":"True","type":"Ready"}`},
+		{template: "plain text", want: "plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q): %v", tt.template, err)
+			}
+			if got := path.Execute(pod); got != tt.want {
+				t.Errorf("Execute(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, template := range []string{
+		"{.metadata.name",
+		"{range .items[*]}{.name}",
+		"{.name}{end}",
+		"{.items[abc]}",
+		"{.items[0}",
+		"{metadata}",
+		`{"unterminated}`,
+	} {
+		t.Run(template, func(t *testing.T) {
+			if _, err := ParseJSONPath(template); err == nil {
+				t.Errorf("ParseJSONPath(%q) succeeded, want an error", template)
+			}
+		})
+	}
+}
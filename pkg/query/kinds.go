@@ -0,0 +1,78 @@
+package query
+
+import (
+	"sort"
+	"strings"
+)
+
+var shortNames = map[string]string{
+	"po":     "Pod",
+	"svc":    "Service",
+	"deploy": "Deployment",
+	"rs":     "ReplicaSet",
+	"sts":    "StatefulSet",
+	"ds":     "DaemonSet",
+	"cm":     "ConfigMap",
+	"ns":     "Namespace",
+	"no":     "Node",
+	"pvc":    "PersistentVolumeClaim",
+	"pv":     "PersistentVolume",
+	"sa":     "ServiceAccount",
+	"ing":    "Ingress",
+	"ep":     "Endpoints",
+	"cj":     "CronJob",
+	"quota":  "ResourceQuota",
+	"limits": "LimitRange",
+	"netpol": "NetworkPolicy",
+	"crd":    "CustomResourceDefinition",
+	"sc":     "StorageClass",
+}
+
+// resolveKind maps what a user types after "get" (a kind, its plural, its
+// short name, or a lowercase form of any of those) to a Kind present in the
+// snapshot. Unknown types resolve to "".
+func resolveKind(input string, kinds []string) string {
+	lower := strings.ToLower(input)
+	if idx := strings.Index(lower, "."); idx >= 0 {
+		lower = lower[:idx]
+	}
+	if kind, ok := shortNames[lower]; ok {
+		lower = strings.ToLower(kind)
+	}
+	for _, kind := range kinds {
+		k := strings.ToLower(kind)
+		if lower == k || lower == plural(k) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// ShortNames lists the kubectl short names that resolve to kind.
+func ShortNames(kind string) []string {
+	var names []string
+	for short, target := range shortNames {
+		if target == kind {
+			names = append(names, short)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plural returns the lowercase resource name for a kind, e.g. "ingresses"
+// for Ingress, as used in API paths.
+func Plural(kind string) string {
+	return plural(strings.ToLower(kind))
+}
+
+func plural(kind string) string {
+	switch {
+	case strings.HasSuffix(kind, "s"), strings.HasSuffix(kind, "x"):
+		return kind + "es"
+	case strings.HasSuffix(kind, "y") && !strings.HasSuffix(kind, "ey"):
+		return strings.TrimSuffix(kind, "y") + "ies"
+	default:
+		return kind + "s"
+	}
+}
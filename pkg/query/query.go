@@ -0,0 +1,373 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/graph"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/selectors"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+const helpText = `Commands:
+  get TYPE[,TYPE...] [NAME...] [-n NAMESPACE] [-l key=value] [-o wide|name|json|yaml|jsonpath=TEMPLATE] [--show-labels]
+  get TYPE/NAME [-o ...]
+  describe TYPE NAME | TYPE/NAME | TYPE [-n NAMESPACE] [-l key=value]
+  api-resources
+  help
+  exit
+
+Without -n, every namespace is searched. A leading "kubectl" is ignored.
+`
+
+// Session answers kubectl-style commands from a loaded snapshot.
+type Session struct {
+	snapshot *models.Snapshot
+	kinds    []string
+	now      time.Time
+	graph    *graph.Graph
+	out      io.Writer
+}
+
+func NewSession(snap *models.Snapshot, out io.Writer) *Session {
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, resource := range snap.Resources {
+		if resource != nil && !seen[resource.Kind] {
+			seen[resource.Kind] = true
+			kinds = append(kinds, resource.Kind)
+		}
+	}
+	sort.Strings(kinds)
+
+	// Ages are shown as of capture time, not as of now.
+	now, err := time.Parse(time.RFC3339, snap.Timestamp)
+	if err != nil {
+		now = time.Now()
+	}
+	return &Session{snapshot: snap, kinds: kinds, now: now, out: out}
+}
+
+func (s *Session) Execute(line string) error {
+	args, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 && (args[0] == "kubectl" || args[0] == "k") {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	switch args[0] {
+	case "get":
+		return s.get(args[1:])
+	case "describe":
+		return s.describe(args[1:])
+	case "api-resources":
+		return s.apiResources()
+	case "help":
+		_, err := io.WriteString(s.out, helpText)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q (try \"help\")", args[0])
+	}
+}
+
+type selection struct {
+	namespace  string
+	selector   string
+	output     string
+	showLabels bool
+}
+
+func newFlagSet(name string, sel *selection) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&sel.namespace, "namespace", "", "")
+	fs.StringVar(&sel.namespace, "n", "", "")
+	fs.StringVar(&sel.selector, "selector", "", "")
+	fs.StringVar(&sel.selector, "l", "", "")
+	fs.Bool("all-namespaces", false, "")
+	fs.Bool("A", false, "")
+	return fs
+}
+
+// parseFlags handles kubectl's attached short forms such as -owide and
+// -nkube-system before handing off to the flag package.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var normalized []string
+	for _, arg := range args {
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' && strings.ContainsRune("onl", rune(arg[1])) && arg[2] != '=' {
+			normalized = append(normalized, arg[:2], arg[2:])
+			continue
+		}
+		normalized = append(normalized, arg)
+	}
+	positional, err := utils.ParseInterspersed(fs, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fs.Name(), err)
+	}
+	return positional, nil
+}
+
+// resolve turns "TYPE NAME...", "TYPE/NAME..." or "TYPE,TYPE" into the kinds
+// and optional names requested.
+func (s *Session) resolve(positional []string) ([]string, []string, error) {
+	if len(positional) == 0 {
+		return nil, nil, fmt.Errorf("you must specify the type of resource to get")
+	}
+
+	var kinds, names []string
+	if strings.Contains(positional[0], "/") {
+		for _, arg := range positional {
+			parts := strings.SplitN(arg, "/", 2)
+			kind := resolveKind(parts[0], s.kinds)
+			if kind == "" {
+				return nil, nil, fmt.Errorf("the snapshot has no resources of type %q", parts[0])
+			}
+			if len(kinds) > 0 && kinds[0] != kind {
+				return nil, nil, fmt.Errorf("mixing resource types in TYPE/NAME form is not supported")
+			}
+			kinds = []string{kind}
+			names = append(names, parts[1])
+		}
+		return kinds, names, nil
+	}
+
+	for _, typ := range strings.Split(positional[0], ",") {
+		kind := resolveKind(typ, s.kinds)
+		if kind == "" {
+			return nil, nil, fmt.Errorf("the snapshot has no resources of type %q", typ)
+		}
+		kinds = append(kinds, kind)
+	}
+	names = positional[1:]
+	if len(names) > 0 && len(kinds) > 1 {
+		return nil, nil, fmt.Errorf("names cannot be combined with multiple resource types")
+	}
+	return kinds, names, nil
+}
+
+// find applies the same namespace, type and label filtering as snapshot
+// capture, then narrows to the requested names.
+func (s *Session) find(kind string, names []string, sel selection) ([]*models.KubernetesResource, error) {
+	if _, err := selectors.ParseLabelSelector(sel.selector); err != nil {
+		return nil, err
+	}
+	options := models.NewDefaultOptions()
+	options.Namespace = sel.namespace
+	options.ResourceType = kind
+	options.LabelSelector = sel.selector
+
+	var matched []*models.KubernetesResource
+	for _, resource := range utils.FilterResources(s.snapshot.Resources, options) {
+		if resource != nil && resource.KubernetesResourceMeta != nil && resource.Kind == kind {
+			matched = append(matched, resource)
+		}
+	}
+
+	if len(names) > 0 {
+		byName := make(map[string][]*models.KubernetesResource)
+		for _, resource := range matched {
+			byName[resource.Name()] = append(byName[resource.Name()], resource)
+		}
+		matched = nil
+		for _, name := range names {
+			found, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("%s %q not found", strings.ToLower(plural(kind)), name)
+			}
+			matched = append(matched, found...)
+		}
+		return matched, nil
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Namespace() != matched[j].Namespace() {
+			return matched[i].Namespace() < matched[j].Namespace()
+		}
+		return matched[i].Name() < matched[j].Name()
+	})
+	return matched, nil
+}
+
+func (s *Session) get(args []string) error {
+	var sel selection
+	fs := newFlagSet("get", &sel)
+	fs.StringVar(&sel.output, "output", "", "")
+	fs.StringVar(&sel.output, "o", "", "")
+	fs.BoolVar(&sel.showLabels, "show-labels", false, "")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	kinds, names, err := s.resolve(positional)
+	if err != nil {
+		return err
+	}
+
+	var groups [][]*models.KubernetesResource
+	var all []*models.KubernetesResource
+	for _, kind := range kinds {
+		resources, err := s.find(kind, names, sel)
+		if err != nil {
+			return err
+		}
+		if len(resources) > 0 {
+			groups = append(groups, resources)
+			all = append(all, resources...)
+		}
+	}
+	single := len(names) == 1 && len(all) == 1
+
+	switch {
+	case sel.output == "" || sel.output == "wide":
+		if len(all) == 0 {
+			_, err := io.WriteString(s.out, "No resources found.\n")
+			return err
+		}
+		for i, group := range groups {
+			if i > 0 {
+				fmt.Fprintln(s.out)
+			}
+			if err := s.writeTable(group, sel, len(kinds) > 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case sel.output == "name":
+		for _, resource := range all {
+			fmt.Fprintf(s.out, "%s/%s\n", strings.ToLower(resource.Kind), resource.Name())
+		}
+		return nil
+	case sel.output == "json":
+		data, err := json.MarshalIndent(document(all, single), "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = s.out.Write(append(data, '\n'))
+		return err
+	case sel.output == "yaml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document(all, single)); err != nil {
+			return err
+		}
+		_, err := s.out.Write(buf.Bytes())
+		return err
+	case strings.HasPrefix(sel.output, "jsonpath="):
+		template, err := ParseJSONPath(strings.TrimPrefix(sel.output, "jsonpath="))
+		if err != nil {
+			return err
+		}
+		// Round-trip through JSON so numbers and nested maps match what
+		// kubectl's JSONPath sees.
+		var data interface{}
+		raw, err := json.Marshal(document(all, single))
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return err
+		}
+		_, err = io.WriteString(s.out, template.Execute(data))
+		return err
+	default:
+		return fmt.Errorf("unsupported output format %q (use wide, name, json, yaml or jsonpath=...)", sel.output)
+	}
+}
+
+// document is what -o json/yaml/jsonpath operate on: the object itself for
+// a single named resource, otherwise a List, as in kubectl.
+func document(resources []*models.KubernetesResource, single bool) interface{} {
+	if single {
+		return resources[0].Object()
+	}
+	items := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		items = append(items, resource.Object())
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	}
+}
+
+func (s *Session) apiResources() error {
+	counts := make(map[string]int)
+	namespaced := make(map[string]bool)
+	apiVersions := make(map[string]string)
+	for _, resource := range s.snapshot.Resources {
+		if resource == nil || resource.KubernetesResourceMeta == nil {
+			continue
+		}
+		counts[resource.Kind]++
+		apiVersions[resource.Kind] = resource.APIVersion
+		if resource.Namespace() != "" {
+			namespaced[resource.Kind] = true
+		}
+	}
+
+	rows := [][]string{{"NAME", "APIVERSION", "NAMESPACED", "KIND", "COUNT"}}
+	for _, kind := range s.kinds {
+		rows = append(rows, []string{
+			plural(strings.ToLower(kind)),
+			apiVersions[kind],
+			fmt.Sprint(namespaced[kind]),
+			kind,
+			fmt.Sprint(counts[kind]),
+		})
+	}
+	return writeRows(s.out, rows)
+}
+
+// splitArgs splits a command line on whitespace, honouring single and
+// double quotes so that jsonpath templates survive intact.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,380 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/health"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/utils"
+)
+
+// columns returns the kubectl-style headers for a kind and a function that
+// renders a row; wide adds the columns kubectl shows with -o wide.
+func (s *Session) columns(kind string, wide bool) ([]string, func(*models.KubernetesResource, map[string]interface{}, map[string]interface{}) []string) {
+	switch kind {
+	case "Pod":
+		headers := []string{"READY", "STATUS", "RESTARTS", "AGE"}
+		if wide {
+			headers = append(headers, "IP", "NODE")
+		}
+		return headers, func(r *models.KubernetesResource, spec, status map[string]interface{}) []string {
+			row := []string{podReady(spec, status), podStatus(r, status), fmt.Sprint(podRestarts(status)), s.age(r)}
+			if wide {
+				row = append(row, valueOr(status["podIP"], "<none>"), valueOr(spec["nodeName"], "<none>"))
+			}
+			return row
+		}
+	case "Deployment", "StatefulSet", "ReplicaSet", "DaemonSet":
+		headers := replicaHeaders(kind)
+		headers = append(headers, "AGE")
+		if wide {
+			headers = append(headers, "CONTAINERS", "IMAGES")
+			if kind != "StatefulSet" {
+				headers = append(headers, "SELECTOR")
+			}
+		}
+		return headers, func(r *models.KubernetesResource, spec, status map[string]interface{}) []string {
+			row := append(replicaColumns(kind, spec, status), s.age(r))
+			if wide {
+				names, images := containerSummary(r.Kind, spec)
+				row = append(row, names, images)
+				if kind != "StatefulSet" {
+					row = append(row, labelSelector(spec["selector"]))
+				}
+			}
+			return row
+		}
+	case "Service":
+		headers := []string{"TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"}
+		if wide {
+			headers = append(headers, "SELECTOR")
+		}
+		return headers, func(r *models.KubernetesResource, spec, status map[string]interface{}) []string {
+			row := []string{valueOr(spec["type"], "ClusterIP"), valueOr(spec["clusterIP"], "<none>"),
+				externalIP(spec, status), servicePorts(spec), s.age(r)}
+			if wide {
+				row = append(row, orNone(utils.FormatSelector(utils.StringMap(spec["selector"]))))
+			}
+			return row
+		}
+	case "Node":
+		headers := []string{"STATUS", "ROLES", "AGE", "VERSION"}
+		if wide {
+			headers = append(headers, "INTERNAL-IP", "OS-IMAGE", "KERNEL-VERSION", "CONTAINER-RUNTIME")
+		}
+		return headers, func(r *models.KubernetesResource, spec, status map[string]interface{}) []string {
+			info, _ := status["nodeInfo"].(map[string]interface{})
+			row := []string{health.Evaluate(r).Detail, nodeRoles(r), s.age(r), valueOr(info["kubeletVersion"], "")}
+			if wide {
+				row = append(row, nodeAddress(status, "InternalIP"), valueOr(info["osImage"], ""),
+					valueOr(info["kernelVersion"], ""), valueOr(info["containerRuntimeVersion"], ""))
+			}
+			return row
+		}
+	case "Namespace":
+		return []string{"STATUS", "AGE"}, func(r *models.KubernetesResource, _, status map[string]interface{}) []string {
+			return []string{valueOr(status["phase"], "Active"), s.age(r)}
+		}
+	case "ConfigMap", "Secret":
+		return []string{"DATA", "AGE"}, func(r *models.KubernetesResource, _, _ map[string]interface{}) []string {
+			data, _ := r.Object()["data"].(map[string]interface{})
+			return []string{fmt.Sprint(len(data)), s.age(r)}
+		}
+	default:
+		return []string{"AGE"}, func(r *models.KubernetesResource, _, _ map[string]interface{}) []string {
+			return []string{s.age(r)}
+		}
+	}
+}
+
+// Table renders resources of one kind the way "get" prints them: headers,
+// the number of leading columns shown without -o wide, and one row per
+// resource. The first column is always the name.
+func (s *Session) Table(resources []*models.KubernetesResource) ([]string, int, [][]string) {
+	kind := resources[0].Kind
+	narrow, _ := s.columns(kind, false)
+	headers, row := s.columns(kind, true)
+
+	var rows [][]string
+	for _, resource := range resources {
+		spec, _ := resource.Spec.Decode()
+		status, _ := resource.Status.Decode()
+		rows = append(rows, append([]string{resource.Name()}, row(resource, spec, status)...))
+	}
+	return append([]string{"NAME"}, headers...), len(narrow) + 1, rows
+}
+
+func (s *Session) writeTable(resources []*models.KubernetesResource, sel selection, qualifyNames bool) error {
+	kind := resources[0].Kind
+	headers, row := s.columns(kind, sel.output == "wide")
+
+	namespaced := false
+	for _, resource := range resources {
+		if resource.Namespace() != "" {
+			namespaced = true
+			break
+		}
+	}
+	showNamespace := namespaced && sel.namespace == ""
+
+	header := []string{"NAME"}
+	if showNamespace {
+		header = append([]string{"NAMESPACE"}, header...)
+	}
+	header = append(header, headers...)
+	if sel.showLabels {
+		header = append(header, "LABELS")
+	}
+
+	rows := [][]string{header}
+	for _, resource := range resources {
+		spec, _ := resource.Spec.Decode()
+		status, _ := resource.Status.Decode()
+
+		name := resource.Name()
+		if qualifyNames {
+			name = strings.ToLower(kind) + "/" + name
+		}
+		cells := []string{name}
+		if showNamespace {
+			cells = append([]string{resource.Namespace()}, cells...)
+		}
+		cells = append(cells, row(resource, spec, status)...)
+		if sel.showLabels {
+			cells = append(cells, orNone(utils.FormatSelector(resource.KubernetesResourceMeta.LabelMap())))
+		}
+		rows = append(rows, cells)
+	}
+	return writeRows(s.out, rows)
+}
+
+func writeRows(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (s *Session) age(resource *models.KubernetesResource) string {
+	created, err := time.Parse(time.RFC3339, resource.KubernetesResourceMeta.CreationTimestamp)
+	if err != nil {
+		return "<unknown>"
+	}
+	return humanDuration(s.now.Sub(created))
+}
+
+// humanDuration follows kubectl's compact age format: 45s, 5m10s, 3h, 2d4h.
+func humanDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	seconds := int(d.Seconds())
+	minutes := int(d.Minutes())
+	hours := int(d.Hours())
+	switch {
+	case seconds < 120:
+		return fmt.Sprintf("%ds", seconds)
+	case minutes < 10:
+		if s := seconds % 60; s > 0 {
+			return fmt.Sprintf("%dm%ds", minutes, s)
+		}
+		return fmt.Sprintf("%dm", minutes)
+	case minutes < 180:
+		return fmt.Sprintf("%dm", minutes)
+	case hours < 8:
+		if m := minutes % 60; m > 0 {
+			return fmt.Sprintf("%dh%dm", hours, m)
+		}
+		return fmt.Sprintf("%dh", hours)
+	case hours < 48:
+		return fmt.Sprintf("%dh", hours)
+	case hours < 24*8:
+		if h := hours % 24; h > 0 {
+			return fmt.Sprintf("%dd%dh", hours/24, h)
+		}
+		return fmt.Sprintf("%dd", hours/24)
+	case hours < 24*365*2:
+		return fmt.Sprintf("%dd", hours/24)
+	default:
+		return fmt.Sprintf("%dy", hours/24/365)
+	}
+}
+
+func podReady(spec, status map[string]interface{}) string {
+	containers := utils.Containers(spec, "containers")
+	statuses, _ := status["containerStatuses"].([]interface{})
+	ready := 0
+	for _, item := range statuses {
+		containerStatus, _ := item.(map[string]interface{})
+		if r, _ := containerStatus["ready"].(bool); r {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(containers))
+}
+
+func podStatus(resource *models.KubernetesResource, status map[string]interface{}) string {
+	if resource.KubernetesResourceMeta.DeletionTimestamp != "" {
+		return "Terminating"
+	}
+	if reason := health.WaitingReason(status); reason != "" {
+		return reason
+	}
+	statuses, _ := status["containerStatuses"].([]interface{})
+	for _, item := range statuses {
+		containerStatus, _ := item.(map[string]interface{})
+		state, _ := containerStatus["state"].(map[string]interface{})
+		terminated, _ := state["terminated"].(map[string]interface{})
+		if reason, _ := terminated["reason"].(string); reason != "" && status["phase"] != "Succeeded" {
+			return reason
+		}
+	}
+	if reason, _ := status["reason"].(string); reason != "" {
+		return reason
+	}
+	return valueOr(status["phase"], "Unknown")
+}
+
+func podRestarts(status map[string]interface{}) int64 {
+	var restarts int64
+	statuses, _ := status["containerStatuses"].([]interface{})
+	for _, item := range statuses {
+		containerStatus, _ := item.(map[string]interface{})
+		restarts += health.Int(containerStatus["restartCount"])
+	}
+	return restarts
+}
+
+func replicaHeaders(kind string) []string {
+	switch kind {
+	case "Deployment":
+		return []string{"READY", "UP-TO-DATE", "AVAILABLE"}
+	case "StatefulSet":
+		return []string{"READY"}
+	case "ReplicaSet":
+		return []string{"DESIRED", "CURRENT", "READY"}
+	default:
+		return []string{"DESIRED", "CURRENT", "READY", "UP-TO-DATE", "AVAILABLE"}
+	}
+}
+
+func replicaColumns(kind string, spec, status map[string]interface{}) []string {
+	desired := health.Int(spec["replicas"])
+	if _, ok := spec["replicas"]; !ok {
+		desired = 1
+	}
+	ready := health.Int(status["readyReplicas"])
+	switch kind {
+	case "Deployment":
+		return []string{fmt.Sprintf("%d/%d", ready, desired),
+			fmt.Sprint(health.Int(status["updatedReplicas"])), fmt.Sprint(health.Int(status["availableReplicas"]))}
+	case "StatefulSet":
+		return []string{fmt.Sprintf("%d/%d", ready, desired)}
+	case "ReplicaSet":
+		return []string{fmt.Sprint(desired), fmt.Sprint(health.Int(status["replicas"])), fmt.Sprint(ready)}
+	default:
+		return []string{
+			fmt.Sprint(health.Int(status["desiredNumberScheduled"])),
+			fmt.Sprint(health.Int(status["currentNumberScheduled"])),
+			fmt.Sprint(health.Int(status["numberReady"])),
+			fmt.Sprint(health.Int(status["updatedNumberScheduled"])),
+			fmt.Sprint(health.Int(status["numberAvailable"])),
+		}
+	}
+}
+
+func containerSummary(kind string, spec map[string]interface{}) (string, string) {
+	var names, images []string
+	for _, container := range utils.Containers(utils.PodSpec(kind, spec), "containers") {
+		name, _ := container["name"].(string)
+		image, _ := container["image"].(string)
+		names = append(names, name)
+		images = append(images, image)
+	}
+	return strings.Join(names, ","), strings.Join(images, ",")
+}
+
+func labelSelector(value interface{}) string {
+	selector, _ := value.(map[string]interface{})
+	return orNone(utils.FormatSelector(utils.StringMap(selector["matchLabels"])))
+}
+
+func servicePorts(spec map[string]interface{}) string {
+	ports, _ := spec["ports"].([]interface{})
+	var parts []string
+	for _, item := range ports {
+		port, _ := item.(map[string]interface{})
+		part := fmt.Sprint(health.Int(port["port"]))
+		if nodePort := health.Int(port["nodePort"]); nodePort > 0 {
+			part += fmt.Sprintf(":%d", nodePort)
+		}
+		parts = append(parts, part+"/"+valueOr(port["protocol"], "TCP"))
+	}
+	return orNone(strings.Join(parts, ","))
+}
+
+func externalIP(spec, status map[string]interface{}) string {
+	var ips []string
+	loadBalancer, _ := status["loadBalancer"].(map[string]interface{})
+	ingress, _ := loadBalancer["ingress"].([]interface{})
+	for _, item := range ingress {
+		entry, _ := item.(map[string]interface{})
+		if ip, _ := entry["ip"].(string); ip != "" {
+			ips = append(ips, ip)
+		} else if hostname, _ := entry["hostname"].(string); hostname != "" {
+			ips = append(ips, hostname)
+		}
+	}
+	external, _ := spec["externalIPs"].([]interface{})
+	for _, item := range external {
+		if ip, _ := item.(string); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 && spec["type"] == "LoadBalancer" {
+		return "<pending>"
+	}
+	return orNone(strings.Join(ips, ","))
+}
+
+func nodeRoles(resource *models.KubernetesResource) string {
+	var roles []string
+	for key := range resource.KubernetesResourceMeta.LabelMap() {
+		if role, ok := strings.CutPrefix(key, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return orNone(strings.Join(roles, ","))
+}
+
+func nodeAddress(status map[string]interface{}, addressType string) string {
+	addresses, _ := status["addresses"].([]interface{})
+	for _, item := range addresses {
+		address, _ := item.(map[string]interface{})
+		if address["type"] == addressType {
+			return valueOr(address["address"], "<none>")
+		}
+	}
+	return "<none>"
+}
+
+func valueOr(value interface{}, fallback string) string {
+	if s, ok := value.(string); ok && s != "" {
+		return s
+	}
+	return fallback
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule keeps the newest snapshot in each of the Count most recent periods
+// that have one. The "last" period keeps the Count newest snapshots.
+type Rule struct {
+	Period string
+	Count  int
+}
+
+// Policy is a set of rules; a snapshot kept by any rule survives.
+type Policy []Rule
+
+var periods = map[string]func(time.Time) string{
+	"last":    nil,
+	"hourly":  func(t time.Time) string { return t.Format("2006-01-02T15") },
+	"daily":   func(t time.Time) string { return t.Format("2006-01-02") },
+	"weekly":  func(t time.Time) string { y, w := t.ISOWeek(); return fmt.Sprintf("%d-W%02d", y, w) },
+	"monthly": func(t time.Time) string { return t.Format("2006-01") },
+}
+
+// ParsePolicy reads a policy such as "hourly=24,daily=30": one snapshot per
+// hour for the last 24 hours with snapshots and one per day for 30 days.
+func ParsePolicy(value string) (Policy, error) {
+	var policy Policy
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		period, countText, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid retention rule %q (expected period=count)", part)
+		}
+		period = strings.TrimSpace(period)
+		if _, known := periods[period]; !known {
+			return nil, fmt.Errorf("unknown retention period %q (use last, hourly, daily, weekly or monthly)", period)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countText))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid count in retention rule %q", part)
+		}
+		policy = append(policy, Rule{Period: period, Count: count})
+	}
+	return policy, nil
+}
+
+func (p Policy) String() string {
+	parts := make([]string, 0, len(p))
+	for _, rule := range p {
+		parts = append(parts, fmt.Sprintf("%s=%d", rule.Period, rule.Count))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Expired returns the indexes of times the policy does not keep. An empty
+// policy keeps everything.
+func (p Policy) Expired(times []time.Time) []int {
+	if len(p) == 0 {
+		return nil
+	}
+
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return times[order[a]].After(times[order[b]]) })
+
+	keep := make(map[int]bool)
+	for _, rule := range p {
+		bucket := periods[rule.Period]
+		seen := make(map[string]bool)
+		for n, i := range order {
+			if bucket == nil {
+				if n < rule.Count {
+					keep[i] = true
+				}
+				continue
+			}
+			key := bucket(times[i])
+			if seen[key] {
+				continue
+			}
+			if len(seen) == rule.Count {
+				break
+			}
+			seen[key] = true
+			keep[i] = true
+		}
+	}
+
+	var expired []int
+	for i := range times {
+		if !keep[i] {
+			expired = append(expired, i)
+		}
+	}
+	return expired
+}
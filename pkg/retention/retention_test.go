@@ -0,0 +1,85 @@
+package retention
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Policy
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "last=5", want: Policy{{Period: "last", Count: 5}}},
+		{value: " hourly = 24 , daily=30,", want: Policy{{Period: "hourly", Count: 24}, {Period: "daily", Count: 30}}},
+		{value: "weekly=0", want: Policy{{Period: "weekly", Count: 0}}},
+		{value: "daily", wantErr: true},
+		{value: "yearly=1", wantErr: true},
+		{value: "daily=x", wantErr: true},
+		{value: "daily=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePolicy(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicy(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyString(t *testing.T) {
+	policy := Policy{{Period: "hourly", Count: 24}, {Period: "daily", Count: 7}}
+	if got := policy.String(); got != "hourly=24,daily=7" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	// Deliberately out of order: Expired sorts by time itself.
+	times := []time.Time{
+		at("2024-01-01T10:00:00Z"), // 0
+		at("2024-01-03T09:00:00Z"), // 1
+		at("2024-01-03T10:30:00Z"), // 2
+		at("2024-01-02T10:00:00Z"), // 3
+		at("2024-01-03T10:00:00Z"), // 4
+		at("2024-01-02T08:00:00Z"), // 5
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		want   []int
+	}{
+		{name: "empty policy keeps everything", policy: "", want: nil},
+		{name: "last keeps the newest", policy: "last=2", want: []int{0, 1, 3, 5}},
+		{name: "daily keeps the newest per day", policy: "daily=2", want: []int{0, 1, 4, 5}},
+		{name: "hourly", policy: "hourly=3", want: []int{0, 4, 5}},
+		{name: "rules combine", policy: "last=1,daily=3", want: []int{1, 4, 5}},
+		{name: "zero keeps nothing", policy: "monthly=0", want: []int{0, 1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := policy.Expired(times); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
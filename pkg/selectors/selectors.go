@@ -0,0 +1,195 @@
+package selectors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	validKey   = regexp.MustCompile(`^[A-Za-z0-9][-A-Za-z0-9_./]*$`)
+	validValue = regexp.MustCompile(`^[^\s=!(),]*$`)
+)
+
+type requirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+// LabelSelector is a parsed label selector supporting the equality (=, ==,
+// !=), set (in, notin) and existence (key, !key) forms.
+type LabelSelector []requirement
+
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var result LabelSelector
+	for _, term := range splitTerms(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		req, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, req)
+	}
+	return result, nil
+}
+
+func parseRequirement(term string) (requirement, error) {
+	req, err := splitRequirement(term)
+	if err != nil {
+		return requirement{}, err
+	}
+	if !validKey.MatchString(req.key) {
+		return requirement{}, fmt.Errorf("invalid selector %q: invalid key %q", term, req.key)
+	}
+	for _, value := range req.values {
+		if !validValue.MatchString(value) {
+			return requirement{}, fmt.Errorf("invalid selector %q: invalid value %q", term, value)
+		}
+	}
+	return req, nil
+}
+
+func splitRequirement(term string) (requirement, error) {
+	if strings.HasPrefix(term, "!") {
+		return requirement{key: strings.TrimSpace(term[1:]), operator: "!"}, nil
+	}
+	for _, op := range []string{" notin ", " in "} {
+		if idx := strings.Index(term, op); idx >= 0 {
+			set := strings.TrimSpace(term[idx+len(op):])
+			if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+				return requirement{}, fmt.Errorf("invalid label selector %q: expected a parenthesized set", term)
+			}
+			var values []string
+			for _, value := range strings.Split(set[1:len(set)-1], ",") {
+				values = append(values, strings.TrimSpace(value))
+			}
+			return requirement{key: strings.TrimSpace(term[:idx]), operator: strings.TrimSpace(op), values: values}, nil
+		}
+	}
+	for _, op := range []string{"!=", "==", "="} {
+		if idx := strings.Index(term, op); idx >= 0 {
+			operator := op
+			if op == "==" {
+				operator = "="
+			}
+			return requirement{
+				key:      strings.TrimSpace(term[:idx]),
+				operator: operator,
+				values:   []string{strings.TrimSpace(term[idx+len(op):])},
+			}, nil
+		}
+	}
+	return requirement{key: term, operator: "exists"}, nil
+}
+
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.key]
+		switch req.operator {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!":
+			if ok {
+				return false
+			}
+		case "=", "in":
+			if !ok || !containsString(req.values, value) {
+				return false
+			}
+		case "!=", "notin":
+			if ok && containsString(req.values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FieldSelector is a parsed field selector. Any dotted field
+// of the object can be compared with =, == or !=, which covers the
+// metadata.name, metadata.namespace, spec.nodeName and status.phase
+// selectors clients commonly send.
+type FieldSelector []requirement
+
+func ParseFieldSelector(selector string) (FieldSelector, error) {
+	var result FieldSelector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		req, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		if req.operator != "=" && req.operator != "!=" {
+			return nil, fmt.Errorf("invalid field selector %q: only =, == and != are supported", term)
+		}
+		result = append(result, req)
+	}
+	return result, nil
+}
+
+func (s FieldSelector) Matches(object map[string]interface{}) bool {
+	for _, req := range s {
+		value := fieldValue(object, req.key)
+		if (value == req.values[0]) != (req.operator == "=") {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldValue(object map[string]interface{}, path string) string {
+	var current interface{} = object
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[part]
+	}
+	switch v := current.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTerms splits a selector on commas that are not inside a set.
+func splitTerms(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
@@ -0,0 +1,84 @@
+package selectors
+
+import "testing"
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend", "env": "prod"}
+	tests := []struct {
+		selector string
+		want     bool
+		wantErr  bool
+	}{
+		{selector: "", want: true},
+		{selector: "app=web", want: true},
+		{selector: "app==web", want: true},
+		{selector: "app=db", want: false},
+		{selector: "app!=db", want: true},
+		{selector: "missing!=x", want: true},
+		{selector: "app", want: true},
+		{selector: "missing", want: false},
+		{selector: "!missing", want: true},
+		{selector: "!app", want: false},
+		{selector: "env in (prod, staging)", want: true},
+		{selector: "env notin (prod,staging)", want: false},
+		{selector: "missing notin (a)", want: true},
+		{selector: "app=web,env in (dev,prod),!debug", want: true},
+		{selector: "app=web,tier=backend", want: false},
+		{selector: "env in prod", wantErr: true},
+		{selector: "=web", wantErr: true},
+		{selector: "app=web=db", wantErr: true},
+		{selector: "app in (web, db", wantErr: true},
+		{selector: "app web", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLabelSelector(%q) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("%q matches = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldSelector(t *testing.T) {
+	object := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-0", "namespace": "default"},
+		"spec":     map[string]interface{}{"nodeName": "node-1", "replicas": float64(3)},
+		"status":   map[string]interface{}{"phase": "Running"},
+	}
+	tests := []struct {
+		selector string
+		want     bool
+		wantErr  bool
+	}{
+		{selector: "", want: true},
+		{selector: "metadata.name=web-0", want: true},
+		{selector: "metadata.namespace==kube-system", want: false},
+		{selector: "status.phase!=Pending,spec.nodeName=node-1", want: true},
+		{selector: "spec.replicas=3", want: true},
+		{selector: "spec.missing=", want: true},
+		{selector: "metadata.name.deeper=x", want: false},
+		{selector: "metadata.name", wantErr: true},
+		{selector: "status.phase in (Running)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseFieldSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFieldSelector(%q) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := selector.Matches(object); got != tt.want {
+				t.Errorf("%q matches = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
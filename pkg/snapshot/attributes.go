@@ -0,0 +1,85 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const (
+	AttributeFormatString = "string"
+	AttributeFormatObject = "object"
+)
+
+type attributePath struct {
+	parent string
+	field  string
+}
+
+// MeshSync stores these fields as JSON-encoded strings; the object attribute
+// format writes them as native JSON instead.
+var encodedAttributePaths = []attributePath{
+	{parent: "spec", field: "attribute"},
+	{parent: "status", field: "attribute"},
+	{parent: "metadata", field: "ownerReferences"},
+	{parent: "metadata", field: "finalizers"},
+	{parent: "metadata", field: "managedFields"},
+}
+
+func expandAttributes(snapshot interface{}) {
+	forEachEncodedAttribute(snapshot, func(parent map[string]interface{}, field string, value interface{}) {
+		encoded, ok := value.(string)
+		if !ok || encoded == "" {
+			return
+		}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(encoded)))
+		decoder.UseNumber()
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return
+		}
+		parent[field] = decoded
+	})
+}
+
+func collapseAttributes(snapshot interface{}) error {
+	var collapseErr error
+	forEachEncodedAttribute(snapshot, func(parent map[string]interface{}, field string, value interface{}) {
+		if _, ok := value.(string); ok || value == nil || collapseErr != nil {
+			return
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			collapseErr = err
+			return
+		}
+		parent[field] = string(encoded)
+	})
+	return collapseErr
+}
+
+func forEachEncodedAttribute(snapshot interface{}, fn func(parent map[string]interface{}, field string, value interface{})) {
+	root, ok := snapshot.(map[string]interface{})
+	if !ok {
+		return
+	}
+	resources, ok := root["resources"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, item := range resources {
+		resource, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, path := range encodedAttributePaths {
+			parent, ok := resource[path.parent].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if value, exists := parent[path.field]; exists {
+				fn(parent, path.field, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+var volatileStatusFields = map[string]bool{
+	"lastHeartbeatTime":  true,
+	"lastProbeTime":      true,
+	"lastTransitionTime": true,
+	"lastUpdateTime":     true,
+}
+
+// Canonicalize returns copies of resources in a run-independent form: sorted
+// by GVK/namespace/name, labels and annotations sorted, and fields that change
+// without any real change to the object removed.
+func Canonicalize(resources []*models.KubernetesResource) []*models.KubernetesResource {
+	canonical := make([]*models.KubernetesResource, 0, len(resources))
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		canonical = append(canonical, canonicalResource(resource))
+	}
+
+	sort.SliceStable(canonical, func(i, j int) bool {
+		return resourceSortKey(canonical[i]) < resourceSortKey(canonical[j])
+	})
+	return canonical
+}
+
+func canonicalResource(resource *models.KubernetesResource) *models.KubernetesResource {
+	copied := *resource
+
+	if resource.KubernetesResourceMeta != nil {
+		meta := *resource.KubernetesResourceMeta
+		meta.ResourceVersion = ""
+		meta.ManagedFields = ""
+		meta.Labels = sortedKeyValues(meta.Labels)
+		meta.Annotations = sortedKeyValues(meta.Annotations)
+		copied.KubernetesResourceMeta = &meta
+	}
+
+	if resource.Status != nil {
+		status := *resource.Status
+		status.Attribute = stripVolatileStatus(status.Attribute)
+		copied.Status = &status
+	}
+
+	return &copied
+}
+
+func sortedKeyValues(values []*models.KubernetesKeyValue) []*models.KubernetesKeyValue {
+	if len(values) == 0 {
+		return values
+	}
+	sorted := make([]*models.KubernetesKeyValue, len(values))
+	copy(sorted, values)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Key != sorted[j].Key {
+			return sorted[i].Key < sorted[j].Key
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	return sorted
+}
+
+func stripVolatileStatus(attribute string) string {
+	if attribute == "" {
+		return attribute
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(attribute)))
+	decoder.UseNumber()
+	var status interface{}
+	if err := decoder.Decode(&status); err != nil {
+		return attribute
+	}
+
+	stripped, err := json.Marshal(removeFields(status, volatileStatusFields))
+	if err != nil {
+		return attribute
+	}
+	return string(stripped)
+}
+
+func removeFields(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if fields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeFields(child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = removeFields(child, fields)
+		}
+	}
+	return value
+}
+
+// marshalTransformed re-encodes the snapshot through a generic tree so that
+// keys come out sorted, then applies the canonical and attribute-format
+// rewrites that the typed model cannot express.
+func marshalTransformed(snapshot map[string]interface{}, options *models.Options) ([]byte, error) {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot to JSON: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to transform snapshot: %w", err)
+	}
+
+	if options.AttributeFormat == AttributeFormatObject {
+		expandAttributes(generic)
+	}
+	if options.Canonical {
+		generic = pruneEmptyIDs(generic)
+	}
+
+	data, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot to JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func pruneEmptyIDs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if (key == "id" || key == "unique_id") && child == "" {
+				delete(v, key)
+				continue
+			}
+			v[key] = pruneEmptyIDs(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = pruneEmptyIDs(child)
+		}
+	}
+	return value
+}
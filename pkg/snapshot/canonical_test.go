@@ -0,0 +1,115 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func testResource(apiVersion, kind, namespace, name string) *models.KubernetesResource {
+	return &models.KubernetesResource{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		KubernetesResourceMeta: &models.KubernetesResourceObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestCanonicalizeSortsResources(t *testing.T) {
+	resources := []*models.KubernetesResource{
+		testResource("v1", "Pod", "default", "b"),
+		nil,
+		testResource("apps/v1", "Deployment", "default", "web"),
+		testResource("v1", "Pod", "default", "a"),
+		testResource("v1", "Namespace", "", "default"),
+	}
+	canonical := Canonicalize(resources)
+
+	var got []string
+	for _, r := range canonical {
+		got = append(got, r.Kind+"/"+r.KubernetesResourceMeta.Name)
+	}
+	want := []string{"Deployment/web", "Namespace/default", "Pod/a", "Pod/b"}
+	if len(got) != len(want) {
+		t.Fatalf("Canonicalize order = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Canonicalize order = %v, want %v", got, want)
+		}
+	}
+	if resources[0].KubernetesResourceMeta.Name != "b" {
+		t.Errorf("Canonicalize reordered its input")
+	}
+}
+
+func TestCanonicalizeStripsVolatileFields(t *testing.T) {
+	original := testResource("v1", "Pod", "default", "web")
+	original.KubernetesResourceMeta.ResourceVersion = "123"
+	original.KubernetesResourceMeta.ManagedFields = `[{"manager":"kubectl"}]`
+	original.KubernetesResourceMeta.Labels = []*models.KubernetesKeyValue{
+		{Key: "tier", Value: "web"},
+		{Key: "app", Value: "shop"},
+	}
+	original.Status = &models.KubernetesResourceStatus{
+		Attribute: `{"conditions":[{"type":"Ready","lastTransitionTime":"2024-01-01T00:00:00Z","lastProbeTime":null}],"phase":"Running"}`,
+	}
+
+	canonical := Canonicalize([]*models.KubernetesResource{original})[0]
+	meta := canonical.KubernetesResourceMeta
+	if meta.ResourceVersion != "" || meta.ManagedFields != "" {
+		t.Errorf("resourceVersion %q and managedFields %q were kept", meta.ResourceVersion, meta.ManagedFields)
+	}
+	if meta.Labels[0].Key != "app" || meta.Labels[1].Key != "tier" {
+		t.Errorf("labels not sorted: %s, %s", meta.Labels[0].Key, meta.Labels[1].Key)
+	}
+	if want := `{"conditions":[{"type":"Ready"}],"phase":"Running"}`; canonical.Status.Attribute != want {
+		t.Errorf("status = %s, want %s", canonical.Status.Attribute, want)
+	}
+	if original.KubernetesResourceMeta.ResourceVersion != "123" || original.KubernetesResourceMeta.Labels[0].Key != "tier" {
+		t.Errorf("Canonicalize modified the original resource")
+	}
+}
+
+func TestStripVolatileStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute string
+		want      string
+	}{
+		{name: "empty", attribute: "", want: ""},
+		{name: "not JSON", attribute: "{broken", want: "{broken"},
+		{name: "nested", attribute: `{"a":{"lastUpdateTime":"x","b":[{"lastHeartbeatTime":"y","c":1}]}}`, want: `{"a":{"b":[{"c":1}]}}`},
+		{name: "large numbers kept", attribute: `{"observedGeneration":12345678901234567890}`, want: `{"observedGeneration":12345678901234567890}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripVolatileStatus(tt.attribute); got != tt.want {
+				t.Errorf("stripVolatileStatus(%s) = %s, want %s", tt.attribute, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneEmptyIDs(t *testing.T) {
+	value := map[string]interface{}{
+		"id": "",
+		"labels": []interface{}{
+			map[string]interface{}{"id": "", "unique_id": "", "key": "app"},
+			map[string]interface{}{"id": "kept", "key": "tier"},
+		},
+	}
+	pruned := pruneEmptyIDs(value).(map[string]interface{})
+	if _, ok := pruned["id"]; ok {
+		t.Errorf("empty top-level id kept")
+	}
+	labels := pruned["labels"].([]interface{})
+	if first := labels[0].(map[string]interface{}); len(first) != 1 {
+		t.Errorf("first label = %v, want only its key", first)
+	}
+	if second := labels[1].(map[string]interface{}); second["id"] != "kept" {
+		t.Errorf("non-empty id removed: %v", second)
+	}
+}
@@ -0,0 +1,155 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const ageHeader = "age-encryption.org/v1\n"
+
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageHeader)) || bytes.HasPrefix(data, []byte(armor.Header))
+}
+
+func Encrypt(data []byte, options *models.Options) ([]byte, error) {
+	recipients, err := encryptionRecipients(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise encryption: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to encrypt snapshot: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalise encryption: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func Decrypt(data []byte, options *models.Options) ([]byte, error) {
+	identities, err := decryptionIdentities(options)
+	if err != nil {
+		return nil, err
+	}
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("snapshot is encrypted; provide --identity or --passphrase-file")
+	}
+
+	var src io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(data, []byte(armor.Header)) {
+		src = armor.NewReader(src)
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt snapshot: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decrypted snapshot: %w", err)
+	}
+	return plaintext, nil
+}
+
+func encryptionRecipients(options *models.Options) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+
+	for _, value := range options.EncryptTo {
+		recipient, err := age.ParseX25519Recipient(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", value, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if options.PassphraseFile != "" {
+		// age refuses to mix scrypt recipients with any other recipient type.
+		if len(recipients) > 0 {
+			return nil, fmt.Errorf("--encrypt-to and --passphrase-file cannot be combined")
+		}
+		passphrase, err := readPassphrase(options.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key from passphrase: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no encryption recipients configured")
+	}
+	return recipients, nil
+}
+
+func decryptionIdentities(options *models.Options) ([]age.Identity, error) {
+	var identities []age.Identity
+	if options == nil {
+		return identities, nil
+	}
+
+	if options.IdentityFile != "" {
+		f, err := os.Open(options.IdentityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open identity file: %w", err)
+		}
+		defer f.Close()
+
+		parsed, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse identity file %s: %w", options.IdentityFile, err)
+		}
+		identities = append(identities, parsed...)
+	}
+
+	if options.PassphraseFile != "" {
+		passphrase, err := readPassphrase(options.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key from passphrase: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+func readPassphrase(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open passphrase file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		return "", fmt.Errorf("passphrase file %s is empty", path)
+	}
+
+	passphrase := strings.TrimRight(scanner.Text(), "\r")
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", path)
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,195 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+const SignatureExtension = ".sig"
+
+// CanonicalResources renders resources in a stable form: resources sorted by
+// apiVersion/kind/namespace/name/uid and every JSON object with sorted keys.
+func CanonicalResources(resources []*models.KubernetesResource) ([]byte, error) {
+	sorted := make([]*models.KubernetesResource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return resourceSortKey(sorted[i]) < resourceSortKey(sorted[j])
+	})
+	canonical, err := canonicalJSON(sorted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalise resources: %w", err)
+	}
+	return canonical, nil
+}
+
+// SignedHeader renders what a signature covers: the header fields and the
+// resource digest in sha256, so that neither the resources nor the
+// cluster, time and options they were captured with can be changed without
+// invalidating the signature.
+func SignedHeader(snap *models.Snapshot) ([]byte, error) {
+	canonical, err := canonicalJSON(map[string]interface{}{
+		"version":          snap.Version,
+		"cluster_id":       snap.ClusterID,
+		"timestamp":        snap.Timestamp,
+		"attribute_format": snap.AttributeFormat,
+		"plugin_info":      snap.PluginInfo,
+		"filter_options":   snap.FilterOptions,
+		"sha256":           snap.SHA256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalise snapshot header: %w", err)
+	}
+	return canonical, nil
+}
+
+// canonicalJSON encodes value with every JSON object's keys sorted.
+// Round-tripping through interface{} makes encoding/json emit map keys in
+// sorted order; UseNumber keeps integers exactly as they were.
+func canonicalJSON(value interface{}) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
+
+func ComputeDigest(resources []*models.KubernetesResource) (string, error) {
+	canonical, err := CanonicalResources(resources)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SignSnapshot signs the snapshot's SignedHeader. SHA256 must already hold
+// the resource digest.
+func SignSnapshot(snap *models.Snapshot, privateKeyPath string) ([]byte, error) {
+	key, err := loadPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	header, err := SignedHeader(snap)
+	if err != nil {
+		return nil, err
+	}
+	signature := ed25519.Sign(key, header)
+	return []byte(base64.StdEncoding.EncodeToString(signature) + "\n"), nil
+}
+
+// VerifySignature checks a signature over the snapshot's SignedHeader. It
+// does not recompute the resource digest; compare ComputeDigest with SHA256
+// first.
+func VerifySignature(snap *models.Snapshot, signature []byte, publicKeyPath string) error {
+	key, err := loadPublicKey(publicKeyPath)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	header, err := SignedHeader(snap)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, header, decoded) {
+		return fmt.Errorf("signature does not match snapshot header and digest")
+	}
+	return nil
+}
+
+func WriteSignature(snapshotPath, privateKeyPath string, snap *models.Snapshot) (string, error) {
+	signature, err := SignSnapshot(snap, privateKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	sigPath := snapshotPath + SignatureExtension
+	if err := os.WriteFile(sigPath, signature, 0644); err != nil {
+		return "", fmt.Errorf("failed to write signature file: %w", err)
+	}
+	return sigPath, nil
+}
+
+func resourceSortKey(resource *models.KubernetesResource) string {
+	if resource == nil {
+		return ""
+	}
+	namespace, name, uid := "", "", ""
+	if resource.KubernetesResourceMeta != nil {
+		namespace = resource.KubernetesResourceMeta.Namespace
+		name = resource.KubernetesResourceMeta.Name
+		uid = resource.KubernetesResourceMeta.UID
+	}
+	return strings.Join([]string{resource.APIVersion, resource.Kind, namespace, name, uid}, "\x00")
+}
+
+func loadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+func loadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,110 @@
+package snapshot
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestComputeDigestIsOrderIndependent(t *testing.T) {
+	a := testResource("v1", "Pod", "default", "a")
+	b := testResource("apps/v1", "Deployment", "default", "b")
+
+	first, err := ComputeDigest([]*models.KubernetesResource{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ComputeDigest([]*models.KubernetesResource{b, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("digest depends on resource order: %s != %s", first, second)
+	}
+
+	b.KubernetesResourceMeta.Name = "c"
+	changed, err := ComputeDigest([]*models.KubernetesResource{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == first {
+		t.Errorf("digest did not change with the resources")
+	}
+}
+
+func writeKeys(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privatePath = filepath.Join(dir, "key.pem")
+	publicPath = filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func TestSignatureCoversHeader(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+	resources := []*models.KubernetesResource{testResource("v1", "Pod", "default", "a")}
+	resources[0].ClusterID = "cluster-1"
+
+	options := models.NewDefaultOptions()
+	options.SignKeyFile = privatePath
+	options.CollectionTime = 5 * time.Second
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if _, err := SaveSnapshot(resources, path, options); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	signature, err := os.ReadFile(path + SignatureExtension)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(*models.Snapshot)
+		wantErr bool
+	}{
+		{name: "unchanged", tamper: func(*models.Snapshot) {}},
+		{name: "cluster ID", tamper: func(s *models.Snapshot) { s.ClusterID = "other" }, wantErr: true},
+		{name: "timestamp", tamper: func(s *models.Snapshot) { s.Timestamp = "2000-01-01T00:00:00Z" }, wantErr: true},
+		{name: "filter options", tamper: func(s *models.Snapshot) { s.FilterOptions["namespaces"] = "kube-system" }, wantErr: true},
+		{name: "plugin info", tamper: func(s *models.Snapshot) { s.PluginInfo["version"] = "9.9.9" }, wantErr: true},
+		{name: "digest", tamper: func(s *models.Snapshot) { s.SHA256 = "00" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := LoadFromFile(path, models.NewDefaultOptions())
+			if err != nil {
+				t.Fatalf("LoadFromFile: %v", err)
+			}
+			tt.tamper(loaded)
+			err = VerifySignature(loaded, signature, publicPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func LoadFromFile(filePath string, options *models.Options) (*models.Snapshot, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	if IsEncrypted(data) {
+		data, err = Decrypt(data, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return Parse(data)
+}
+
+func Parse(data []byte) (*models.Snapshot, error) {
+	var snap models.Snapshot
+	err := json.Unmarshal(data, &snap)
+	if err == nil {
+		return &snap, nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return nil, fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+
+	// Snapshots written with the object attribute format carry native JSON
+	// where the model expects MeshSync's encoded strings.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+	if err := collapseAttributes(generic); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot attributes: %w", err)
+	}
+
+	normalised, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+
+	snap = models.Snapshot{}
+	if err := json.Unmarshal(normalised, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+	return &snap, nil
+}
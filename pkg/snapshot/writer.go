@@ -3,6 +3,7 @@ package snapshot
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -11,67 +12,125 @@ import (
 )
 
 func SaveToFile(resources []*models.KubernetesResource, filePath string, options *models.Options) error {
-	if options.VerboseMode {
-		fmt.Printf("Saving %d resources to %s\n", len(resources), filePath)
+	_, err := SaveSnapshot(resources, filePath, options)
+	return err
+}
+
+// SaveSnapshot writes the snapshot like SaveToFile and returns it as
+// written, header included.
+func SaveSnapshot(resources []*models.KubernetesResource, filePath string, options *models.Options) (*models.Snapshot, error) {
+	slog.Debug("Encoding snapshot", "resources", len(resources), "path", filePath)
+
+	if options.Canonical {
+		resources = Canonicalize(resources)
+	}
+
+	digest, err := ComputeDigest(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute snapshot digest: %w", err)
 	}
 
+	pluginInfo := getPluginInfo()
+	if options.Canonical || options.OmitTimestamps {
+		// created_at only repeats the top-level timestamp.
+		delete(pluginInfo, "created_at")
+	}
+
+	saved := &models.Snapshot{
+		Version:       "v1",
+		SHA256:        digest,
+		Timestamp:     time.Now().Format(time.RFC3339),
+		ClusterID:     ClusterID(resources),
+		PluginInfo:    pluginInfo,
+		FilterOptions: FilterOptions(options),
+		Resources:     resources,
+	}
 	snapshot := map[string]interface{}{
-		"version": "v1",
-		"timestamp": time.Now().Format(time.RFC3339),
+		"version": saved.Version,
+		"sha256": saved.SHA256,
+		"timestamp": saved.Timestamp,
 		"resources": resources,
-		"cluster_id": getClusterID(resources),
-		"plugin_info": getPluginInfo(),
-		"filter_options": getFilterOptions(options),
+		"cluster_id": saved.ClusterID,
+		"plugin_info": pluginInfo,
+		"filter_options": saved.FilterOptions,
+	}
+
+	if options.OmitTimestamps {
+		saved.Timestamp = ""
+		delete(snapshot, "timestamp")
+	}
+	if options.AttributeFormat == AttributeFormatObject {
+		saved.AttributeFormat = AttributeFormatObject
+		snapshot["attribute_format"] = AttributeFormatObject
 	}
 
 	var data []byte
-	var err error
 
 	if options.OutputFormat == "yaml" {
 		//TODO: Implement YAML output format
-		return fmt.Errorf("YAML output format not yet implemented")
+		return nil, fmt.Errorf("YAML output format not yet implemented")
+	} else if options.Canonical || options.AttributeFormat == AttributeFormatObject {
+		data, err = marshalTransformed(snapshot, options)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 
 		data, err = json.MarshalIndent(snapshot, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal snapshot to JSON: %w", err)
+			return nil, fmt.Errorf("failed to marshal snapshot to JSON: %w", err)
 		}
 	}
 
-	if options.VerboseMode {
-		fmt.Printf("JSON size: %d bytes\n", len(data))
+	slog.Debug("Snapshot encoded", "bytes", len(data))
+
+	if options.EncryptionEnabled() {
+		data, err = Encrypt(data, options)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("Snapshot encrypted", "bytes", len(data))
 	}
 
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
-		if options.VerboseMode {
-			fmt.Printf("Warning: Could not get absolute path: %v\n", err)
-		}
+		slog.Debug("Could not get absolute path", "path", filePath, "error", err)
 		absPath = filePath
 	}
 
-	if options.VerboseMode {
-		fmt.Printf("Writing to absolute path: %s\n", absPath)
+	fileMode := os.FileMode(0644)
+	if options.EncryptionEnabled() {
+		fileMode = 0600
 	}
 
-	err = os.WriteFile(absPath, data, 0644)
+	err = os.WriteFile(absPath, data, fileMode)
 	if err != nil {
-		return fmt.Errorf("failed to write snapshot to file: %w", err)
+		return nil, fmt.Errorf("failed to write snapshot to file: %w", err)
 	}
 
 	if _, err := os.Stat(absPath); err != nil {
-		return fmt.Errorf("failed to verify file was created: %w", err)
+		return nil, fmt.Errorf("failed to verify file was created: %w", err)
 	}
 
-	if options.VerboseMode {
-		fmt.Printf("File successfully written: %s (%d bytes)\n", absPath, len(data))
+	slog.Debug("Snapshot written", "path", absPath, "bytes", len(data))
+
+	if options.SignKeyFile != "" {
+		sigPath, err := WriteSignature(absPath, options.SignKeyFile, saved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign snapshot: %w", err)
+		}
+		slog.Debug("Signature written", "path", sigPath)
 	}
-	return nil
+	return saved, nil
 }
 
-func getClusterID(resources []*models.KubernetesResource) string {
-	if len(resources) > 0 && resources[0] != nil {
-		return resources[0].ClusterID
+// ClusterID returns the first cluster ID carried by the resources, or
+// "unknown" when none has one.
+func ClusterID(resources []*models.KubernetesResource) string {
+	for _, resource := range resources {
+		if resource != nil && resource.ClusterID != "" {
+			return resource.ClusterID
+		}
 	}
 	return "unknown"
 }
@@ -85,7 +144,7 @@ func getPluginInfo() map[string]string {
 	}
 }
 
-func getFilterOptions(options *models.Options) map[string]interface{} {
+func FilterOptions(options *models.Options) map[string]interface{} {
 	result := map[string]interface{}{
 		"namespaces": options.Namespace,
 		"resource_type": options.ResourceType,
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	DefaultKeyTemplate = "{cluster_id}/{timestamp}.json.gz"
+	DefaultEndpoint    = "s3.amazonaws.com"
+
+	// partSize bounds memory use for streamed (gzip) uploads, which are
+	// always multipart because their size is not known up front.
+	partSize = 16 * 1024 * 1024
+)
+
+// S3Config holds connection settings. Empty fields fall back to the
+// standard AWS environment variables, then the shared credentials file and
+// instance metadata for credentials.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	Insecure  bool
+}
+
+type Location struct {
+	Bucket string
+	Prefix string
+}
+
+func (l Location) String() string {
+	if l.Prefix == "" {
+		return "s3://" + l.Bucket
+	}
+	return "s3://" + l.Bucket + "/" + l.Prefix
+}
+
+func ParseS3URL(raw string) (Location, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return Location{}, fmt.Errorf("invalid S3 location %q (expected s3://bucket/prefix)", raw)
+	}
+	return Location{Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// ListOptions narrows a listing. Cluster matches keys that contain the
+// cluster ID as a path segment, which the default key template guarantees.
+type ListOptions struct {
+	Cluster string
+	Since   time.Time
+	Until   time.Time
+}
+
+func (o ListOptions) matches(object Object) bool {
+	if o.Cluster != "" && !containsSegment(object.Key, o.Cluster) {
+		return false
+	}
+	if !o.Since.IsZero() && object.LastModified.Before(o.Since) {
+		return false
+	}
+	if !o.Until.IsZero() && object.LastModified.After(o.Until) {
+		return false
+	}
+	return true
+}
+
+func containsSegment(key, segment string) bool {
+	for _, part := range strings.Split(key, "/") {
+		if part == segment {
+			return true
+		}
+	}
+	return false
+}
+
+type S3Client struct {
+	client *minio.Client
+}
+
+func NewS3Client(cfg S3Config) (*S3Client, error) {
+	endpoint := firstNonEmpty(cfg.Endpoint, os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL"), DefaultEndpoint)
+	secure := !cfg.Insecure
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		endpoint = u.Host
+		secure = u.Scheme != "http"
+	}
+	region := firstNonEmpty(cfg.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+
+	var creds *credentials.Credentials
+	if cfg.AccessKey != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	} else {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.FileAWSCredentials{},
+			&credentials.IAM{},
+		})
+	}
+
+	lookup := minio.BucketLookupAuto
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        creds,
+		Secure:       secure,
+		Region:       region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return &S3Client{client: client}, nil
+}
+
+// ExpandKey fills a key template. Supported placeholders are {cluster_id},
+// {timestamp} (UTC, 20060102T150405Z), {date} (2006-01-02) and {filename}.
+func ExpandKey(template, clusterID, localPath string, now time.Time) string {
+	now = now.UTC()
+	replacer := strings.NewReplacer(
+		"{cluster_id}", clusterID,
+		"{timestamp}", now.Format("20060102T150405Z"),
+		"{date}", now.Format("2006-01-02"),
+		"{filename}", filepath.Base(localPath),
+	)
+	return replacer.Replace(template)
+}
+
+// Upload stores a local file under loc.Prefix/key. Keys ending in .gz are
+// gzip-compressed on the way up. The client retries failed requests and
+// switches to multipart uploads for large or streamed bodies.
+func (c *S3Client) Upload(ctx context.Context, loc Location, key, localPath string, metadata map[string]string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	fullKey := joinKey(loc.Prefix, key)
+	opts := minio.PutObjectOptions{
+		ContentType:  "application/json",
+		UserMetadata: metadata,
+		PartSize:     partSize,
+	}
+
+	var body io.Reader = file
+	size := int64(-1)
+	if strings.HasSuffix(key, ".gz") {
+		opts.ContentType = "application/gzip"
+		reader, writer := io.Pipe()
+		// PutObject may return before reading everything, on an error
+		// for instance; closing the reader unblocks the gzip goroutine.
+		defer reader.Close()
+		go func() {
+			gz := gzip.NewWriter(writer)
+			_, err := io.Copy(gz, file)
+			if err == nil {
+				err = gz.Close()
+			}
+			writer.CloseWithError(err)
+		}()
+		body = reader
+	} else if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	if _, err := c.client.PutObject(ctx, loc.Bucket, fullKey, body, size, opts); err != nil {
+		return "", fmt.Errorf("failed to upload to s3://%s/%s: %w", loc.Bucket, fullKey, err)
+	}
+	return fullKey, nil
+}
+
+// List returns the objects under the location that match opts, newest first.
+func (c *S3Client) List(ctx context.Context, loc Location, opts ListOptions) ([]Object, error) {
+	prefix := loc.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var objects []Object
+	for info := range c.client.ListObjects(ctx, loc.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", loc, info.Err)
+		}
+		object := Object{Key: info.Key, Size: info.Size, LastModified: info.LastModified}
+		if opts.matches(object) {
+			objects = append(objects, object)
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].LastModified.After(objects[j].LastModified)
+	})
+	return objects, nil
+}
+
+// Fetch downloads an object to localPath, decompressing .gz keys.
+func (c *S3Client) Fetch(ctx context.Context, bucket, key, localPath string) error {
+	object, err := c.client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch s3://%s/%s: %w", bucket, key, err)
+	}
+	defer object.Close()
+
+	var body io.Reader = object
+	if strings.HasSuffix(key, ".gz") {
+		gz, err := gzip.NewReader(object)
+		if err != nil {
+			return fmt.Errorf("failed to fetch s3://%s/%s: %w", bucket, key, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", localPath, err)
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(localPath)
+		return fmt.Errorf("failed to download s3://%s/%s: %w", bucket, key, err)
+	}
+	return file.Close()
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(key, "/")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseS3URL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Location
+		wantErr bool
+	}{
+		{raw: "s3://bucket", want: Location{Bucket: "bucket"}},
+		{raw: "s3://bucket/", want: Location{Bucket: "bucket"}},
+		{raw: "s3://bucket/snapshots/prod/", want: Location{Bucket: "bucket", Prefix: "snapshots/prod"}},
+		{raw: "https://bucket/prefix", wantErr: true},
+		{raw: "s3:///prefix", wantErr: true},
+		{raw: "bucket/prefix", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseS3URL(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseS3URL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseS3URL(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandKey(t *testing.T) {
+	now := time.Date(2024, 3, 9, 23, 30, 5, 0, time.FixedZone("UTC-2", -2*60*60))
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: DefaultKeyTemplate, want: "abc123/20240310T013005Z.json.gz"},
+		{template: "{date}/{filename}", want: "2024-03-10/snap.json"},
+		{template: "static.json", want: "static.json"},
+		{template: "{unknown}/{cluster_id}", want: "{unknown}/abc123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := ExpandKey(tt.template, "abc123", "/tmp/out/snap.json", now); got != tt.want {
+				t.Errorf("ExpandKey(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListOptionsMatches(t *testing.T) {
+	noon := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	object := Object{Key: "snapshots/abc123/20240310T120000Z.json.gz", LastModified: noon}
+	tests := []struct {
+		name string
+		opts ListOptions
+		want bool
+	}{
+		{name: "no filters", opts: ListOptions{}, want: true},
+		{name: "cluster segment", opts: ListOptions{Cluster: "abc123"}, want: true},
+		{name: "other cluster", opts: ListOptions{Cluster: "def456"}, want: false},
+		{name: "cluster prefix is not a segment", opts: ListOptions{Cluster: "abc"}, want: false},
+		{name: "since before", opts: ListOptions{Since: noon.Add(-time.Hour)}, want: true},
+		{name: "since equal", opts: ListOptions{Since: noon}, want: true},
+		{name: "since after", opts: ListOptions{Since: noon.Add(time.Hour)}, want: false},
+		{name: "until after", opts: ListOptions{Until: noon.Add(time.Hour)}, want: true},
+		{name: "until before", opts: ListOptions{Until: noon.Add(-time.Hour)}, want: false},
+		{name: "window", opts: ListOptions{Cluster: "abc123", Since: noon.Add(-time.Hour), Until: noon.Add(time.Hour)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.matches(object); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
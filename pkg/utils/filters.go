@@ -1,17 +1,27 @@
 package utils
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/selectors"
 )
 
+// FilterResources keeps the resources that match the namespace, type and
+// label filters in options. Callers check the label selector with
+// selectors.ParseLabelSelector when they read their flags; a selector that
+// does not parse matches nothing.
 func FilterResources(resources []*models.KubernetesResource, options *models.Options) []*models.KubernetesResource {
 	if options.Namespace == "" && options.ResourceType == "" && 
 	   !options.FastMode && len(options.ExcludeTypes) == 0 && 
 	   options.LabelSelector == "" {
 		return resources
 	}
+	labels, err := selectors.ParseLabelSelector(options.LabelSelector)
+	if err != nil {
+		return nil
+	}
 
 	var filtered []*models.KubernetesResource
 
@@ -42,7 +52,7 @@ func FilterResources(resources []*models.KubernetesResource, options *models.Opt
 		}
 
 		if options.LabelSelector != "" && resource.KubernetesResourceMeta != nil {
-			if !matchesLabelSelector(resource.KubernetesResourceMeta.Labels, options.LabelSelector) {
+			if !labels.Matches(resource.KubernetesResourceMeta.LabelMap()) {
 				continue
 			}
 		}
@@ -53,24 +63,46 @@ func FilterResources(resources []*models.KubernetesResource, options *models.Opt
 	return filtered
 }
 
-func matchesLabelSelector(labels []*models.KubernetesKeyValue, selector string) bool {
-	if selector == "" || len(labels) == 0 {
+func MatchesSelector(selector map[string]string, labels map[string]string) bool {
+	if len(selector) == 0 {
 		return false
 	}
 
-	parts := strings.Split(selector, "=")
-	if len(parts) != 2 {
-		return false
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
 	}
 
-	key := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
+	return true
+}
+
+func StringMap(value interface{}) map[string]string {
+	result := map[string]string{}
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return result
+	}
 
-	for _, label := range labels {
-		if label.Key == key && label.Value == value {
-			return true
+	for key, v := range raw {
+		if s, ok := v.(string); ok {
+			result[key] = s
 		}
 	}
 
-	return false
-}
\ No newline at end of file
+	return result
+}
+
+func FormatSelector(selector map[string]string) string {
+	var keys []string
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, key+"="+selector[key])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
+)
+
+func TestFilterResourcesLabelSelector(t *testing.T) {
+	labelled := func(name string, labels ...string) *models.KubernetesResource {
+		meta := &models.KubernetesResourceObjectMeta{Name: name, Namespace: "default"}
+		for i := 0; i+1 < len(labels); i += 2 {
+			meta.Labels = append(meta.Labels, &models.KubernetesKeyValue{Key: labels[i], Value: labels[i+1]})
+		}
+		return &models.KubernetesResource{Kind: "Pod", KubernetesResourceMeta: meta}
+	}
+	resources := []*models.KubernetesResource{
+		labelled("web", "app", "web", "env", "prod"),
+		labelled("db", "app", "db", "env", "staging"),
+		labelled("plain"),
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "app=web", want: []string{"web"}},
+		{selector: "env in (prod,staging)", want: []string{"web", "db"}},
+		{selector: "app notin (web)", want: []string{"db", "plain"}},
+		{selector: "!app", want: []string{"plain"}},
+		{selector: "app,env!=prod", want: []string{"db"}},
+		{selector: "app in prod", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			options := models.NewDefaultOptions()
+			options.LabelSelector = tt.selector
+			var got []string
+			for _, resource := range FilterResources(resources, options) {
+				got = append(got, resource.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterResources(%q) = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
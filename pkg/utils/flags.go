@@ -0,0 +1,20 @@
+package utils
+
+import "flag"
+
+// ParseInterspersed lets flags follow positional arguments, as in
+// "verify snapshot.json --pub-key key.pem", which flag.Parse alone rejects.
+func ParseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
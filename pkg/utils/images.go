@@ -0,0 +1,59 @@
+package utils
+
+import "strings"
+
+const defaultRegistry = "docker.io"
+
+type ImageReference struct {
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// ParseImageReference splits an image reference such as
+// "registry.k8s.io/coredns/coredns:v1.11.3" or "nginx@sha256:..." into its
+// parts, applying Docker Hub defaults for short names.
+func ParseImageReference(image string) ImageReference {
+	ref := ImageReference{}
+	name := image
+
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		ref.Digest = name[idx+1:]
+		name = name[:idx]
+	}
+
+	if idx := strings.LastIndex(name, ":"); idx >= 0 && !strings.Contains(name[idx:], "/") {
+		ref.Tag = name[idx+1:]
+		name = name[:idx]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		ref.Repository = parts[1]
+	} else {
+		ref.Registry = defaultRegistry
+		ref.Repository = name
+		if !strings.Contains(name, "/") {
+			ref.Repository = "library/" + name
+		}
+	}
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref
+}
+
+// ImageName strips the tag and digest from an image reference, leaving the
+// name exactly as it was written.
+func ImageName(image string) string {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		image = image[:idx]
+	}
+	if idx := strings.LastIndex(image, ":"); idx >= 0 && !strings.Contains(image[idx:], "/") {
+		image = image[:idx]
+	}
+	return image
+}
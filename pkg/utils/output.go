@@ -2,11 +2,13 @@ package utils
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/logging"
 	"github.com/fyzanshaik/kubectl-meshsync_snapshot/pkg/models"
 )
 
@@ -25,10 +27,20 @@ func FormatSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// PrintProgress shows a spinner on stderr until done is closed. When logs
+// are JSON or stderr is not a terminal, where the carriage returns would
+// corrupt them, it logs the start and end instead.
 func PrintProgress(done chan bool, message string, options *models.Options) {
 	if options.QuietMode {
 		return
 	}
+	if !logging.Interactive() {
+		startTime := time.Now()
+		slog.Info(message)
+		<-done
+		slog.Info(message+" done", "elapsed", time.Since(startTime).Round(time.Millisecond).String())
+		return
+	}
 
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
@@ -41,11 +53,11 @@ func PrintProgress(done chan bool, message string, options *models.Options) {
 	for {
 		select {
 		case <-done:
-			fmt.Printf("%s\r%s %s ✓\n", clearLine, spinnerChars[0], message)
+			fmt.Fprintf(os.Stderr, "%s\r%s %s ✓\n", clearLine, spinnerChars[0], message)
 			return
 		case <-ticker.C:
 			elapsed := time.Since(startTime).Round(time.Second)
-			fmt.Printf("\r%s %s (%s elapsed)   ", spinnerChars[i], message, elapsed)
+			fmt.Fprintf(os.Stderr, "\r%s %s (%s elapsed)   ", spinnerChars[i], message, elapsed)
 			i = (i + 1) % len(spinnerChars)
 		}
 	}
@@ -115,4 +127,29 @@ func CreateParentDirs(filepath string) error {
 func GetFilename(path string) string {
 	parts := strings.Split(path, "/")
 	return parts[len(parts)-1]
-}
\ No newline at end of file
+}
+
+// ParseTimestampedFilename reports the capture time encoded in a name
+// produced by GenerateTimestampedFilename for the same base filename.
+func ParseTimestampedFilename(baseFilename, filename string) (time.Time, bool) {
+	ext := ".json"
+	basename := baseFilename
+	if strings.HasSuffix(baseFilename, ".json") {
+		basename = baseFilename[:len(baseFilename)-5]
+	} else if strings.HasSuffix(baseFilename, ".yaml") || strings.HasSuffix(baseFilename, ".yml") {
+		ext = baseFilename[len(baseFilename)-5:]
+		basename = baseFilename[:len(baseFilename)-5]
+	}
+
+	prefix := GetFilename(basename) + "-"
+	name := GetFilename(filename)
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return time.Time{}, false
+	}
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+	t, err := time.ParseInLocation("20060102-150405", timestamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,40 @@
+package utils
+
+// PodSpec returns the pod spec embedded in a decoded workload spec: the spec
+// itself for Pods, spec.template.spec for controllers and
+// spec.jobTemplate.spec.template.spec for CronJobs.
+func PodSpec(kind string, spec map[string]interface{}) map[string]interface{} {
+	switch kind {
+	case "Pod":
+		return spec
+	case "CronJob":
+		jobTemplate, _ := spec["jobTemplate"].(map[string]interface{})
+		jobSpec, _ := jobTemplate["spec"].(map[string]interface{})
+		return templateSpec(jobSpec)
+	default:
+		return templateSpec(spec)
+	}
+}
+
+func Containers(podSpec map[string]interface{}, fields ...string) []map[string]interface{} {
+	if len(fields) == 0 {
+		fields = []string{"initContainers", "containers", "ephemeralContainers"}
+	}
+
+	var containers []map[string]interface{}
+	for _, field := range fields {
+		items, _ := podSpec[field].([]interface{})
+		for _, item := range items {
+			if container, ok := item.(map[string]interface{}); ok {
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
+
+func templateSpec(spec map[string]interface{}) map[string]interface{} {
+	template, _ := spec["template"].(map[string]interface{})
+	podSpec, _ := template["spec"].(map[string]interface{})
+	return podSpec
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var binarySuffixes = map[string]float64{
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+var decimalSuffixes = map[string]float64{
+	"n": 1e-9,
+	"u": 1e-6,
+	"m": 1e-3,
+	"":  1,
+	"k": 1e3,
+	"M": 1e6,
+	"G": 1e9,
+	"T": 1e12,
+	"P": 1e15,
+	"E": 1e18,
+}
+
+// ParseQuantity parses a Kubernetes resource quantity such as "250m",
+// "1.5", "512Mi" or "1e3" into base units (cores for CPU, bytes for memory).
+// Numbers decoded from JSON are accepted as-is.
+func ParseQuantity(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case interface{ Float64() (float64, error) }:
+		return v.Float64()
+	case string:
+		return parseQuantityString(v)
+	case nil:
+		return 0, fmt.Errorf("empty quantity")
+	default:
+		return 0, fmt.Errorf("unsupported quantity type %T", value)
+	}
+}
+
+func parseQuantityString(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("+-0123456789.", r)
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	number, suffix := s[:end], s[end:]
+	if number == "" {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	base, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	if multiplier, ok := binarySuffixes[suffix]; ok {
+		return base * multiplier, nil
+	}
+	if multiplier, ok := decimalSuffixes[suffix]; ok {
+		return base * multiplier, nil
+	}
+	if len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		exponent, err := strconv.Atoi(suffix[1:])
+		if err == nil {
+			return base * math.Pow10(exponent), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid quantity suffix %q in %q", suffix, s)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    float64
+		wantErr bool
+	}{
+		{name: "millicores", value: "250m", want: 0.25},
+		{name: "whole cores", value: "2", want: 2},
+		{name: "fractional cores", value: "1.5", want: 1.5},
+		{name: "nanocores", value: "500n", want: 500e-9},
+		{name: "binary memory", value: "512Mi", want: 512 * 1024 * 1024},
+		{name: "binary memory large", value: "1Ti", want: 1 << 40},
+		{name: "decimal memory", value: "1G", want: 1e9},
+		{name: "kilo", value: "100k", want: 1e5},
+		{name: "exponent", value: "1e3", want: 1000},
+		{name: "upper exponent", value: "12E2", want: 1200},
+		{name: "surrounding space", value: " 64Mi ", want: 64 * 1024 * 1024},
+		{name: "signed", value: "+1", want: 1},
+		{name: "JSON number", value: float64(3), want: 3},
+		{name: "json.Number", value: json.Number("0.5"), want: 0.5},
+		{name: "empty string", value: "", wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+		{name: "unknown suffix", value: "5Xi", wantErr: true},
+		{name: "suffix only", value: "Mi", wantErr: true},
+		{name: "bad number", value: "1.2.3", wantErr: true},
+		{name: "bad exponent", value: "1ex", wantErr: true},
+		{name: "unsupported type", value: []string{"1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuantity(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuantity(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && math.Abs(got-tt.want) > 1e-12*math.Max(1, math.Abs(tt.want)) {
+				t.Errorf("ParseQuantity(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// NameUUID derives a name-based (version 5 style) UUID from parts, so that
+// repeated exports of the same snapshot produce identical IDs.
+func NameUUID(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "/")))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}